@@ -24,6 +24,9 @@ type App struct {
 	ollamaApiDebugger *OllamaApiDebugger
 	httpClient        *core.HttpCli
 	adapterManager    *OpenAIAdapterManager
+	toolRegistry      *ToolRegistry
+	knowledgeBase     *KnowledgeBase
+	embeddings        *EmbeddingPlayground
 }
 
 // NewApp 创建一个新的 App 应用
@@ -50,11 +53,19 @@ func NewApp() *App {
 	app.chatManager = NewChatManager(context.Background(), store, logger)
 	app.modelManager = NewModelManager(app, app.configMgr, logger)
 	app.modelMarket = NewModelMarket(app, logger)
-	app.ollamaApiDebugger = NewOllamaApiDebugger(logger, app.configMgr)
+	app.ollamaApiDebugger = NewOllamaApiDebugger(logger, app.configMgr, store)
 	app.adapterManager = NewOpenAIAdapterManager(logger, store, app.configMgr)
+	app.toolRegistry = NewToolRegistry(store, logger)
+	app.knowledgeBase = NewKnowledgeBase(store, logger)
+	app.knowledgeBase.SetEmbedder(app.modelManager)
+	app.embeddings = NewEmbeddingPlayground(store, app.modelManager, logger)
 
 	// 设置ChatManager的AIProvider
 	app.chatManager.SetAIProvider(NewAIProviderAdapter(app.modelManager, logger))
+	app.chatManager.SetToolRegistry(app.toolRegistry)
+	app.chatManager.SetKnowledgeBase(app.knowledgeBase)
+	app.chatManager.SetPromptUsageRecorder(app.promptEngineering)
+	app.adapterManager.SetPromptEngineering(app.promptEngineering)
 
 	app.httpClient = core.NewHttp(logger.WithPrefix("HttpClient"))
 	if err := app.rebuildDependencies(); err != nil {
@@ -104,6 +115,7 @@ func (a *App) startup(ctx context.Context) {
 	a.modelManager.SetContext(ctx)
 	a.modelMarket.SetContext(ctx)
 	a.chatManager.SetContext(ctx)
+	a.knowledgeBase.SetContext(ctx)
 	a.promptEngineering.Startup(ctx)
 	a.ollamaApiDebugger.SetContext(ctx)
 	a.adapterManager.SetContext(ctx) // 注入 Wails 上下文到适配器管理器
@@ -157,11 +169,119 @@ func (a *App) GetOllamaServers() ([]types.OllamaServerConfig, error) {
 	return a.ollamaApiDebugger.GetOllamaServers()
 }
 
+func (a *App) SendHttpRequestStream(request types.ApiRequest, requestID string) (string, error) {
+	return a.ollamaApiDebugger.SendHttpRequestStream(request, requestID)
+}
+
+func (a *App) CancelApiStream(requestID string) error {
+	return a.ollamaApiDebugger.CancelApiStream(requestID)
+}
+
+func (a *App) ParseCurlCommand(command string) (types.CurlImportResult, error) {
+	return a.ollamaApiDebugger.ParseCurlCommand(command)
+}
+
+func (a *App) GenerateApiSnippets(request types.ApiRequest) (types.ApiCodeSnippets, error) {
+	return a.ollamaApiDebugger.GenerateApiSnippets(request)
+}
+
+func (a *App) ListApiCollections() ([]types.ApiCollection, error) {
+	return a.ollamaApiDebugger.ListApiCollections()
+}
+
+func (a *App) GetApiCollection(id string) (types.ApiCollection, error) {
+	return a.ollamaApiDebugger.GetApiCollection(id)
+}
+
+func (a *App) SaveApiCollection(collection types.ApiCollection) (types.ApiCollection, error) {
+	return a.ollamaApiDebugger.SaveApiCollection(collection)
+}
+
+func (a *App) DeleteApiCollection(id string) error {
+	return a.ollamaApiDebugger.DeleteApiCollection(id)
+}
+
+func (a *App) SaveApiRequest(collectionID string, saved types.ApiSavedRequest) (types.ApiSavedRequest, error) {
+	return a.ollamaApiDebugger.SaveApiRequest(collectionID, saved)
+}
+
+func (a *App) DeleteApiRequest(collectionID string, requestID string) error {
+	return a.ollamaApiDebugger.DeleteApiRequest(collectionID, requestID)
+}
+
+func (a *App) DeleteApiFolder(collectionID string, folder string) error {
+	return a.ollamaApiDebugger.DeleteApiFolder(collectionID, folder)
+}
+
+func (a *App) SelectPostmanCollectionFile() (string, error) {
+	return a.ollamaApiDebugger.SelectPostmanCollectionFile()
+}
+
+func (a *App) ImportPostmanCollection(data string) (types.PostmanImportReport, error) {
+	return a.ollamaApiDebugger.ImportPostmanCollection(data)
+}
+
+func (a *App) ImportPostmanCollectionFromFile(path string) (types.PostmanImportReport, error) {
+	return a.ollamaApiDebugger.ImportPostmanCollectionFromFile(path)
+}
+
+func (a *App) ExportPostmanCollection(collectionID string) (string, error) {
+	return a.ollamaApiDebugger.ExportPostmanCollection(collectionID)
+}
+
+func (a *App) ExportPostmanCollectionToFile(collectionID string) (string, error) {
+	return a.ollamaApiDebugger.ExportPostmanCollectionToFile(collectionID)
+}
+
+func (a *App) ListApiHistory(limit int) ([]types.ApiHistoryEntry, error) {
+	return a.ollamaApiDebugger.ListApiHistory(limit)
+}
+
+func (a *App) GetApiHistoryEntry(id string) (types.ApiHistoryEntry, error) {
+	return a.ollamaApiDebugger.GetApiHistoryEntry(id)
+}
+
+func (a *App) DeleteApiHistoryEntry(id string) error {
+	return a.ollamaApiDebugger.DeleteApiHistoryEntry(id)
+}
+
+func (a *App) ClearApiHistory() error {
+	return a.ollamaApiDebugger.ClearApiHistory()
+}
+
+func (a *App) ReplayApiHistory(id string, serverID string) (types.ApiResponse, error) {
+	return a.ollamaApiDebugger.ReplayApiHistory(id, serverID)
+}
+
 // --- PromptEngineering Methods ---
-func (a *App) GeneratePromptStream(idea string, model string, serverId string) {
-	a.promptEngineering.GeneratePromptStream(idea, model, serverId)
+func (a *App) GeneratePromptStream(idea string, model string, serverId string, profileId string) {
+	a.promptEngineering.GeneratePromptStream(idea, model, serverId, profileId)
+}
+func (a *App) GeneratePromptCandidates(req types.PromptGenerationRequest) (string, error) {
+	return a.promptEngineering.GeneratePromptCandidates(req)
 }
-func (a *App) OptimizePrompt(content string, feedback string, model string, serverId string) (string, error) {
+func (a *App) ListMetaPromptProfiles() ([]types.MetaPromptProfile, error) {
+	return a.promptEngineering.ListMetaPromptProfiles()
+}
+func (a *App) GetMetaPromptProfile(id string) (types.MetaPromptProfile, error) {
+	return a.promptEngineering.GetMetaPromptProfile(id)
+}
+func (a *App) SaveMetaPromptProfile(profile types.MetaPromptProfile) (types.MetaPromptProfile, error) {
+	return a.promptEngineering.SaveMetaPromptProfile(profile)
+}
+func (a *App) DeleteMetaPromptProfile(id string) error {
+	return a.promptEngineering.DeleteMetaPromptProfile(id)
+}
+func (a *App) GetDefaultMetaPromptProfile() (types.MetaPromptProfile, error) {
+	return a.promptEngineering.GetDefaultMetaPromptProfile()
+}
+func (a *App) SetDefaultMetaPromptProfile(id string) error {
+	return a.promptEngineering.SetDefaultMetaPromptProfile(id)
+}
+func (a *App) PreviewMetaPrompt(profile types.MetaPromptProfile) (string, error) {
+	return a.promptEngineering.PreviewMetaPrompt(profile)
+}
+func (a *App) OptimizePrompt(content string, feedback string, model string, serverId string) (types.PromptOptimizeResult, error) {
 	return a.promptEngineering.OptimizePrompt(content, feedback, model, serverId)
 }
 func (a *App) SavePrompt(prompt types.Prompt) error {
@@ -176,6 +296,60 @@ func (a *App) GetPrompt(id string) (types.Prompt, error) {
 func (a *App) DeletePrompt(id string) error {
 	return a.promptEngineering.DeletePrompt(id)
 }
+func (a *App) ListPromptRevisions(promptID string) ([]types.PromptRevision, error) {
+	return a.promptEngineering.ListPromptRevisions(promptID)
+}
+func (a *App) GetPromptRevision(promptID string, version int) (types.PromptRevision, error) {
+	return a.promptEngineering.GetPromptRevision(promptID, version)
+}
+func (a *App) DiffPromptRevisions(promptID string, fromVersion int, toVersion int) (types.PromptRevisionDiff, error) {
+	return a.promptEngineering.DiffPromptRevisions(promptID, fromVersion, toVersion)
+}
+func (a *App) RollbackPrompt(promptID string, version int) (types.Prompt, error) {
+	return a.promptEngineering.RollbackPrompt(promptID, version)
+}
+func (a *App) GetPromptSuite(promptID string) (types.PromptSuite, error) {
+	return a.promptEngineering.GetPromptSuite(promptID)
+}
+func (a *App) SavePromptSuite(suite types.PromptSuite) (types.PromptSuite, error) {
+	return a.promptEngineering.SavePromptSuite(suite)
+}
+func (a *App) RunPromptSuite(promptID string, models []types.ModelTarget) (types.PromptSuiteRun, error) {
+	return a.promptEngineering.RunPromptSuite(promptID, models)
+}
+func (a *App) ListPromptSuiteRuns(promptID string) ([]types.PromptSuiteRun, error) {
+	return a.promptEngineering.ListPromptSuiteRuns(promptID)
+}
+func (a *App) GetPromptUsage(promptID string) (types.PromptUsageStats, error) {
+	return a.promptEngineering.GetPromptUsage(promptID)
+}
+func (a *App) QueryPrompts(query types.PromptQuery) (types.PromptQueryResult, error) {
+	return a.promptEngineering.QueryPrompts(query)
+}
+func (a *App) ListPromptTags() ([]types.PromptFacet, error) {
+	return a.promptEngineering.ListPromptTags()
+}
+func (a *App) ExportPrompts(ids []string, format types.PromptBundleFormat, includeHistory bool) (string, error) {
+	return a.promptEngineering.ExportPrompts(ids, format, includeHistory)
+}
+func (a *App) ExportPromptsToFile(ids []string, format types.PromptBundleFormat, includeHistory bool) (string, error) {
+	return a.promptEngineering.ExportPromptsToFile(ids, format, includeHistory)
+}
+func (a *App) SelectPromptImportFiles() ([]string, error) {
+	return a.promptEngineering.SelectPromptImportFiles()
+}
+func (a *App) ImportPrompts(data string, format types.PromptBundleFormat, strategy types.PromptConflictStrategy, dryRun bool) (types.PromptImportReport, error) {
+	return a.promptEngineering.ImportPrompts(data, format, strategy, dryRun)
+}
+func (a *App) ImportPromptsFromFiles(paths []string, strategy types.PromptConflictStrategy, dryRun bool) (types.PromptImportReport, error) {
+	return a.promptEngineering.ImportPromptsFromFiles(paths, strategy, dryRun)
+}
+func (a *App) GetPromptVariables(id string) ([]types.PromptVariable, error) {
+	return a.promptEngineering.GetPromptVariables(id)
+}
+func (a *App) RenderPrompt(id string, values map[string]interface{}) (string, error) {
+	return a.promptEngineering.RenderPrompt(id, values)
+}
 
 // --- ModelManager Methods ---
 func (a *App) ListModelsByServer(serverID string) ([]types.Model, error) {
@@ -208,11 +382,20 @@ func (a *App) GetModelParams(modelName string) (map[string]interface{}, error) {
 	}
 	return ConvertFromModelParams(params), nil
 }
+func (a *App) Complete(req types.CompletionRequest) (types.CompletionResult, error) {
+	return a.modelManager.Complete(req)
+}
 
 // --- ChatManager Methods ---
 func (a *App) ChatMessage(modelName string, messages []types.Message, stream bool) (string, error) {
 	return a.chatManager.ChatMessage(modelName, messages, stream)
 }
+func (a *App) SendChat(req types.ChatRequest) (types.ChatResponse, error) {
+	return a.chatManager.SendChat(req)
+}
+func (a *App) GenerateStructured(req types.StructuredRequest) (types.StructuredResult, error) {
+	return a.chatManager.GenerateStructured(req)
+}
 func (a *App) ListConversations() ([]*types.Conversation, error) {
 	return a.chatManager.ListConversations()
 }
@@ -225,6 +408,117 @@ func (a *App) GetConversation(id string) (*types.Conversation, error) {
 func (a *App) DeleteConversation(id string) error {
 	return a.chatManager.DeleteConversation(id)
 }
+func (a *App) ExportConversation(id string, format types.ExportFormat) (string, error) {
+	return a.chatManager.ExportConversation(id, format)
+}
+func (a *App) ExportConversations(ids []string, format types.ExportFormat) (string, error) {
+	return a.chatManager.ExportConversations(ids, format)
+}
+func (a *App) ExportConversationsToFile(ids []string, format types.ExportFormat) (string, error) {
+	return a.chatManager.ExportConversationsToFile(ids, format)
+}
+func (a *App) SelectImportFile() (string, error) {
+	return a.chatManager.SelectImportFile()
+}
+func (a *App) ImportConversations(data string, source types.ImportSource, dryRun bool) (types.ImportReport, error) {
+	return a.chatManager.ImportConversations(data, source, dryRun)
+}
+func (a *App) ImportConversationsFromFile(path string, source types.ImportSource, dryRun bool) (types.ImportReport, error) {
+	return a.chatManager.ImportConversationsFromFile(path, source, dryRun)
+}
+func (a *App) ListPersonaTemplates() ([]types.PersonaTemplate, error) {
+	return a.chatManager.ListPersonaTemplates()
+}
+func (a *App) GetPersonaTemplate(id string) (*types.PersonaTemplate, error) {
+	return a.chatManager.GetPersonaTemplate(id)
+}
+func (a *App) SavePersonaTemplate(template types.PersonaTemplate) (*types.PersonaTemplate, error) {
+	return a.chatManager.SavePersonaTemplate(template)
+}
+func (a *App) DeletePersonaTemplate(id string) error {
+	return a.chatManager.DeletePersonaTemplate(id)
+}
+func (a *App) StartConversationFromTemplate(templateID string) (*types.Conversation, error) {
+	return a.chatManager.StartConversationFromTemplate(templateID)
+}
+func (a *App) SelectImageAttachments() ([]types.Attachment, error) {
+	return a.chatManager.SelectImageAttachments()
+}
+func (a *App) AddImageAttachmentFromBase64(name string, data string) (types.Attachment, error) {
+	return a.chatManager.AddImageAttachmentFromBase64(name, data)
+}
+func (a *App) GetAttachmentDataURL(att types.Attachment) (string, error) {
+	return a.chatManager.GetAttachmentDataURL(att)
+}
+
+// --- ToolRegistry Methods ---
+func (a *App) ListTools() ([]types.ToolDefinition, error) {
+	return a.toolRegistry.ListTools()
+}
+func (a *App) SaveTool(tool types.ToolDefinition) error {
+	return a.toolRegistry.SaveTool(tool)
+}
+func (a *App) DeleteTool(name string) error {
+	return a.toolRegistry.DeleteTool(name)
+}
+func (a *App) GetToolSettings() types.ToolSettings {
+	return a.toolRegistry.GetSettings()
+}
+func (a *App) SaveToolSettings(settings types.ToolSettings) error {
+	return a.toolRegistry.SaveSettings(settings)
+}
+
+// --- EmbeddingPlayground Methods ---
+func (a *App) EmbedTexts(serverID string, model string, inputs []string) (types.EmbeddingResult, error) {
+	return a.embeddings.EmbedTexts(serverID, model, inputs)
+}
+func (a *App) SaveEmbeddingSet(name string, serverID string, model string, inputs []string) (*types.EmbeddingSet, error) {
+	return a.embeddings.SaveEmbeddingSet(name, serverID, model, inputs)
+}
+func (a *App) ListEmbeddingSets() ([]types.EmbeddingSet, error) {
+	return a.embeddings.ListEmbeddingSets()
+}
+func (a *App) GetEmbeddingSet(id string) (*types.EmbeddingSet, error) {
+	return a.embeddings.GetEmbeddingSet(id)
+}
+func (a *App) DeleteEmbeddingSet(id string) error {
+	return a.embeddings.DeleteEmbeddingSet(id)
+}
+func (a *App) CompareEmbeddingSets(ids []string) (types.EmbeddingComparison, error) {
+	return a.embeddings.CompareEmbeddingSets(ids)
+}
+
+// --- KnowledgeBase Methods ---
+func (a *App) ListKnowledgeCollections() ([]types.KnowledgeCollection, error) {
+	return a.knowledgeBase.ListCollections()
+}
+func (a *App) GetKnowledgeCollection(id string) (*types.KnowledgeCollection, error) {
+	return a.knowledgeBase.GetCollection(id)
+}
+func (a *App) SaveKnowledgeCollection(collection types.KnowledgeCollection) (*types.KnowledgeCollection, error) {
+	return a.knowledgeBase.SaveCollection(collection)
+}
+func (a *App) DeleteKnowledgeCollection(id string) error {
+	if err := a.knowledgeBase.DeleteCollection(id); err != nil {
+		return err
+	}
+	return a.chatManager.DetachCollection(id)
+}
+func (a *App) SelectKnowledgeFiles() ([]string, error) {
+	return a.knowledgeBase.SelectKnowledgeFiles()
+}
+func (a *App) SelectKnowledgeDirectory() (string, error) {
+	return a.knowledgeBase.SelectKnowledgeDirectory()
+}
+func (a *App) IngestKnowledgeFiles(collectionID string, paths []string) (types.IngestReport, error) {
+	return a.knowledgeBase.IngestFiles(collectionID, paths)
+}
+func (a *App) RemoveKnowledgeDocument(collectionID string, documentID string) error {
+	return a.knowledgeBase.RemoveDocument(collectionID, documentID)
+}
+func (a *App) SearchKnowledge(collectionIDs []string, query string, topK int) ([]types.KnowledgeHit, error) {
+	return a.knowledgeBase.Search(collectionIDs, query, topK)
+}
 
 // --- ConfigManager Methods ---
 func (a *App) GetServers() ([]types.OllamaServerConfig, error) {
@@ -275,7 +569,7 @@ func (a *App) OpenInBrowser(url string) {
 	runtime.BrowserOpenURL(a.ctx, url)
 }
 
-// AIProviderAdapter 适配器，用于解决core.Message和本地Message类型不匹配的问题
+// AIProviderAdapter 适配器，将ChatManager的聊天请求转发给ModelManager
 type AIProviderAdapter struct {
 	modelManager *ModelManager
 	logger       *core.AppLog
@@ -290,22 +584,32 @@ func NewAIProviderAdapter(modelManager *ModelManager, logger *core.AppLog) *AIPr
 }
 
 // Chat 适配Chat方法
-func (a *AIProviderAdapter) Chat(model string, messages []core.Message) (string, error) {
-	a.logger.Debug("Adapter: 开始阻塞式聊天请求", "model", model, "messageCount", len(messages))
-	response, err := a.modelManager.Chat(model, messages)
+func (a *AIProviderAdapter) Chat(req OllamaChatRequest) (ChatResult, error) {
+	a.logger.Debug("Adapter: 开始阻塞式聊天请求", "model", req.Model, "messageCount", len(req.Messages))
+	result, err := a.modelManager.Chat(req)
 	if err != nil {
 		a.logger.Error("Adapter: 阻塞式聊天请求失败", "error", err)
-		return "", err
+		return ChatResult{}, err
 	}
-	return response, nil
+	return result, nil
 }
 
 // ChatStream 适配ChatStream方法
-func (a *AIProviderAdapter) ChatStream(model string, messages []core.Message, callback func(string)) error {
-	a.logger.Debug("Adapter: 开始流式聊天请求", "model", model, "messageCount", len(messages))
-	err := a.modelManager.ChatStream(model, messages, callback)
+func (a *AIProviderAdapter) ChatStream(req OllamaChatRequest, callback func(ChatChunk)) (ChatResult, error) {
+	a.logger.Debug("Adapter: 开始流式聊天请求", "model", req.Model, "messageCount", len(req.Messages))
+	result, err := a.modelManager.ChatStream(req, callback)
 	if err != nil {
 		a.logger.Error("Adapter: 流式聊天请求失败", "error", err)
 	}
-	return err
+	return result, err
+}
+
+// Generate 适配Generate方法
+func (a *AIProviderAdapter) Generate(req OllamaGenerateRequest) (string, error) {
+	a.logger.Debug("Adapter: 开始补全请求", "model", req.Model)
+	response, err := a.modelManager.Generate(req)
+	if err != nil {
+		a.logger.Error("Adapter: 补全请求失败", "error", err)
+	}
+	return response, err
 }
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // 注册 GIF 解码器
+	"image/jpeg"
+	_ "image/png" // 注册 PNG 解码器
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// attachmentsDir 附件文件的保存目录
+	attachmentsDir = "data/attachments"
+	// maxImageAttachmentSize 单张图片的大小上限
+	maxImageAttachmentSize = 20 << 20
+	// maxThumbnailPixels 生成缩略图时允许解码的最大像素数, 防止小体积的超大分辨率图片解码后耗尽内存
+	maxThumbnailPixels = 40_000_000
+	// maxImagesPerMessage 单条消息允许携带的图片数量上限
+	maxImagesPerMessage = 8
+	// thumbnailMaxSide 缩略图最长边的像素数
+	thumbnailMaxSide = 256
+)
+
+// supportedImageTypes 允许作为附件的图片类型及其扩展名
+var supportedImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// SelectImageAttachments 弹出文件选择对话框并将选中的图片保存为附件
+func (cm *ChatManager) SelectImageAttachments() ([]types.Attachment, error) {
+	paths, err := runtime.OpenMultipleFilesDialog(cm.ctx, runtime.OpenDialogOptions{
+		Title: "选择图片",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Images", Pattern: "*.png;*.jpg;*.jpeg;*.gif;*.webp"},
+		},
+	})
+	if err != nil {
+		cm.logger.Error("打开图片选择对话框失败", "error", err)
+		return nil, fmt.Errorf("打开图片选择对话框失败: %w", err)
+	}
+	if len(paths) > maxImagesPerMessage {
+		return nil, fmt.Errorf("一次最多只能选择 %d 张图片", maxImagesPerMessage)
+	}
+
+	attachments := make([]types.Attachment, 0, len(paths))
+	for _, path := range paths {
+		att, err := cm.AddImageAttachment(path)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, att)
+	}
+	return attachments, nil
+}
+
+// AddImageAttachment 读取本地图片文件, 复制到附件目录并生成缩略图
+func (cm *ChatManager) AddImageAttachment(path string) (types.Attachment, error) {
+	cm.logger.Debug("添加图片附件", "path", path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return types.Attachment{}, fmt.Errorf("读取图片失败: %w", err)
+	}
+	if info.Size() > maxImageAttachmentSize {
+		return types.Attachment{}, fmt.Errorf("图片 %s 超过 %d MB 的大小限制", filepath.Base(path), maxImageAttachmentSize>>20)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.Attachment{}, fmt.Errorf("读取图片失败: %w", err)
+	}
+	return cm.saveImageAttachment(filepath.Base(path), data)
+}
+
+// AddImageAttachmentFromBase64 将前端粘贴或拖入的图片(base64 或 data URL)保存为附件
+func (cm *ChatManager) AddImageAttachmentFromBase64(name string, data string) (types.Attachment, error) {
+	if idx := strings.Index(data, ","); strings.HasPrefix(data, "data:") && idx > 0 {
+		data = data[idx+1:]
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return types.Attachment{}, fmt.Errorf("解析图片数据失败: %w", err)
+	}
+	if len(raw) > maxImageAttachmentSize {
+		return types.Attachment{}, fmt.Errorf("图片超过 %d MB 的大小限制", maxImageAttachmentSize>>20)
+	}
+	return cm.saveImageAttachment(name, raw)
+}
+
+// GetAttachmentDataURL 返回附件原图的 data URL, 用于查看大图
+func (cm *ChatManager) GetAttachmentDataURL(att types.Attachment) (string, error) {
+	data, err := readAttachmentData(att)
+	if err != nil {
+		return "", err
+	}
+	return "data:" + att.MimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// saveImageAttachment 校验图片类型, 生成缩略图并写入附件目录
+func (cm *ChatManager) saveImageAttachment(name string, data []byte) (types.Attachment, error) {
+	mimeType := http.DetectContentType(data)
+	ext, ok := supportedImageTypes[mimeType]
+	if !ok {
+		return types.Attachment{}, fmt.Errorf("不支持的图片类型: %s", mimeType)
+	}
+
+	att := types.Attachment{
+		ID:       GenerateUniqueID(),
+		Name:     name,
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+	}
+	if att.Name == "" {
+		att.Name = att.ID + ext
+	}
+
+	// WebP 等标准库无法解码的格式不生成缩略图, 前端回退为占位图标
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		att.Width, att.Height = config.Width, config.Height
+		if int64(config.Width)*int64(config.Height) > maxThumbnailPixels {
+			cm.logger.Warn("图片分辨率过大, 不生成缩略图", "name", att.Name, "width", config.Width, "height", config.Height)
+		} else if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+			if thumb, err := makeThumbnail(img); err == nil {
+				att.Thumbnail = thumb
+			} else {
+				cm.logger.Warn("生成缩略图失败", "name", att.Name, "error", err)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(attachmentsDir, 0755); err != nil {
+		return types.Attachment{}, fmt.Errorf("创建附件目录失败: %w", err)
+	}
+	att.Path = filepath.Join(attachmentsDir, att.ID+ext)
+	if err := os.WriteFile(att.Path, data, 0644); err != nil {
+		cm.logger.Error("保存附件失败", "path", att.Path, "error", err)
+		return types.Attachment{}, fmt.Errorf("保存附件失败: %w", err)
+	}
+
+	cm.logger.Info("图片附件已保存", "id", att.ID, "name", att.Name, "size", att.Size)
+	return att, nil
+}
+
+// removeConversationAttachments 删除对话中引用的附件文件
+// 只删除附件目录内、且没有被其他对话或对话模板引用的文件 (模板的种子消息和重复导入的对话会共享附件)
+func (cm *ChatManager) removeConversationAttachments(conv *types.Conversation) {
+	var referenced map[string]bool
+	for _, msg := range conv.Messages {
+		for _, att := range msg.Attachments {
+			if att.Path == "" {
+				continue
+			}
+			path, err := attachmentFilePath(att.Path)
+			if err != nil {
+				cm.logger.Warn("跳过附件目录之外的附件文件", "path", att.Path)
+				continue
+			}
+			if referenced == nil {
+				if referenced, err = cm.referencedAttachmentPaths(conv.ID); err != nil {
+					cm.logger.Warn("获取附件引用失败, 保留附件文件", "error", err)
+					return
+				}
+			}
+			if referenced[path] {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				cm.logger.Warn("删除附件文件失败", "path", path, "error", err)
+			}
+		}
+	}
+}
+
+// referencedAttachmentPaths 返回除 excludeID 之外的对话以及所有对话模板引用的附件路径
+func (cm *ChatManager) referencedAttachmentPaths(excludeID string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	add := func(messages []types.Message) {
+		for _, msg := range messages {
+			for _, att := range msg.Attachments {
+				if path, err := attachmentFilePath(att.Path); err == nil {
+					referenced[path] = true
+				}
+			}
+		}
+	}
+
+	conversations, err := cm.ListConversations()
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range conversations {
+		if other.ID != excludeID {
+			add(other.Messages)
+		}
+	}
+	templates, err := cm.ListPersonaTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		add(template.SeedMessages)
+	}
+	return referenced, nil
+}
+
+// sanitizeAttachmentPaths 清除不在附件目录中的附件路径, 用于导入的对话, 内联数据保持不变
+func sanitizeAttachmentPaths(conv *types.Conversation) int {
+	cleared := 0
+	for i := range conv.Messages {
+		for j := range conv.Messages[i].Attachments {
+			att := &conv.Messages[i].Attachments[j]
+			if att.Path == "" {
+				continue
+			}
+			if _, err := attachmentFilePath(att.Path); err != nil {
+				att.Path = ""
+				cleared++
+			}
+		}
+	}
+	return cleared
+}
+
+// attachmentFilePath 返回附件文件的绝对路径, 路径不在附件目录中时返回错误,
+// 避免对话中的附件路径被用来读取或删除任意文件
+func attachmentFilePath(path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("附件路径为空")
+	}
+	dir, err := filepath.Abs(attachmentsDir)
+	if err != nil {
+		return "", fmt.Errorf("解析附件目录失败: %w", err)
+	}
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("解析附件路径失败: %w", err)
+	}
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("附件路径不在附件目录中: %s", path)
+	}
+	return target, nil
+}
+
+// readAttachmentData 读取附件原始内容, 优先使用内联数据; 文件只能位于附件目录中
+func readAttachmentData(att types.Attachment) ([]byte, error) {
+	if att.Data != "" {
+		data, err := base64.StdEncoding.DecodeString(att.Data)
+		if err != nil {
+			return nil, fmt.Errorf("解析附件 %s 失败: %w", att.Name, err)
+		}
+		return data, nil
+	}
+	if att.Path == "" {
+		return nil, fmt.Errorf("附件 %s 没有可用的数据", att.Name)
+	}
+	path, err := attachmentFilePath(att.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取附件 %s 失败: %w", att.Name, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取附件 %s 失败: %w", att.Name, err)
+	}
+	return data, nil
+}
+
+// attachmentImages 将消息中的图片附件转换为 /api/chat 所需的 base64 列表
+func attachmentImages(attachments []types.Attachment) ([]string, error) {
+	if len(attachments) > maxImagesPerMessage {
+		return nil, fmt.Errorf("单条消息最多携带 %d 张图片", maxImagesPerMessage)
+	}
+	images := make([]string, 0, len(attachments))
+	for _, att := range attachments {
+		if !strings.HasPrefix(att.MimeType, "image/") {
+			continue
+		}
+		data, err := readAttachmentData(att)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, base64.StdEncoding.EncodeToString(data))
+	}
+	return images, nil
+}
+
+// makeThumbnail 按区域平均缩小图片并编码为 JPEG data URL
+func makeThumbnail(src image.Image) (string, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return "", fmt.Errorf("图片尺寸无效")
+	}
+
+	tw, th := w, h
+	if w > thumbnailMaxSide || h > thumbnailMaxSide {
+		if w >= h {
+			tw, th = thumbnailMaxSide, max(1, h*thumbnailMaxSide/w)
+		} else {
+			tw, th = max(1, w*thumbnailMaxSide/h), thumbnailMaxSide
+		}
+	}
+
+	// RGBA() 返回预乘 alpha 的分量, 求均值后叠加白色背景 (JPEG 不支持透明通道)
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+(x+1)*w/tw
+			var r, g, b, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			bg := 0xffff - a/n
+			dst.Set(x, y, color.RGBA64{R: uint16(r/n + bg), G: uint16(g/n + bg), B: uint16(b/n + bg), A: 0xffff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
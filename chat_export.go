@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// exportTimeLayout 导出文件中使用的时间格式
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// fineTuneMessage 微调数据集中的单条消息 (OpenAI/Ollama 通用的 chat 格式)
+type fineTuneMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []types.ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// fineTuneRecord 微调数据集中的一行记录
+type fineTuneRecord struct {
+	Messages []fineTuneMessage `json:"messages"`
+}
+
+// ExportConversation 将单个对话导出为指定格式的文本
+func (cm *ChatManager) ExportConversation(id string, format types.ExportFormat) (string, error) {
+	cm.logger.Debug("导出对话", "id", id, "format", format)
+	conv, err := cm.GetConversation(id)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := cm.renderConversations([]*types.Conversation{conv}, format, false)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ExportConversations 批量导出对话, ids 为空时导出全部对话
+func (cm *ChatManager) ExportConversations(ids []string, format types.ExportFormat) (string, error) {
+	convs, err := cm.collectConversations(ids)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := cm.renderConversations(convs, format, true)
+	if err != nil {
+		return "", err
+	}
+	cm.logger.Info("批量导出对话完成", "count", len(convs), "format", format)
+	return string(data), nil
+}
+
+// ExportConversationsToFile 弹出保存对话框并将导出内容写入文件
+// 返回写入的文件路径, 用户取消时返回空字符串
+func (cm *ChatManager) ExportConversationsToFile(ids []string, format types.ExportFormat) (string, error) {
+	convs, err := cm.collectConversations(ids)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := cm.renderConversations(convs, format, len(ids) != 1)
+	if err != nil {
+		return "", err
+	}
+
+	defaultName := "conversations"
+	if len(convs) == 1 {
+		defaultName = sanitizeFileName(convs[0].Title)
+	}
+	ext := exportFileExtension(format)
+
+	path, err := runtime.SaveFileDialog(cm.ctx, runtime.SaveDialogOptions{
+		Title:           "导出对话",
+		DefaultFilename: defaultName + ext,
+		Filters: []runtime.FileFilter{
+			{DisplayName: string(format), Pattern: "*" + ext},
+		},
+	})
+	if err != nil {
+		cm.logger.Error("打开保存对话框失败", "error", err)
+		return "", fmt.Errorf("打开保存对话框失败: %w", err)
+	}
+	if path == "" {
+		cm.logger.Debug("用户取消了导出")
+		return "", nil
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		cm.logger.Error("写入导出文件失败", "path", path, "error", err)
+		return "", fmt.Errorf("写入导出文件失败: %w", err)
+	}
+
+	cm.logger.Info("对话已导出到文件", "path", path, "count", len(convs), "format", format)
+	return path, nil
+}
+
+// collectConversations 按ID获取对话, ids 为空时返回全部对话; 结果按创建时间升序排列
+func (cm *ChatManager) collectConversations(ids []string) ([]*types.Conversation, error) {
+	var convs []*types.Conversation
+	if len(ids) == 0 {
+		all, err := cm.ListConversations()
+		if err != nil {
+			return nil, err
+		}
+		convs = all
+	} else {
+		convs = make([]*types.Conversation, 0, len(ids))
+		for _, id := range ids {
+			conv, err := cm.GetConversation(id)
+			if err != nil {
+				return nil, err
+			}
+			convs = append(convs, conv)
+		}
+	}
+
+	sortConversationsByTime(convs)
+	return convs, nil
+}
+
+// sortConversationsByTime 按创建时间升序排列对话
+func sortConversationsByTime(convs []*types.Conversation) {
+	sort.SliceStable(convs, func(i, j int) bool {
+		return convs[i].Timestamp < convs[j].Timestamp
+	})
+}
+
+// renderConversations 按格式渲染对话; bulk 为 true 时 JSON 格式输出数组
+func (cm *ChatManager) renderConversations(convs []*types.Conversation, format types.ExportFormat, bulk bool) ([]byte, error) {
+	switch format {
+	case types.ExportFormatMarkdown:
+		return renderConversationsMarkdown(convs), nil
+	case types.ExportFormatHtml:
+		return renderConversationsHTML(convs)
+	case types.ExportFormatJson:
+		if !bulk && len(convs) == 1 {
+			return json.MarshalIndent(convs[0], "", "  ")
+		}
+		return json.MarshalIndent(convs, "", "  ")
+	case types.ExportFormatJsonl:
+		return renderConversationsJSONL(convs)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// renderConversationsMarkdown 将对话渲染为 Markdown 文本记录
+func renderConversationsMarkdown(convs []*types.Conversation) []byte {
+	var buf bytes.Buffer
+	for i, conv := range convs {
+		if i > 0 {
+			buf.WriteString("\n---\n\n")
+		}
+		fmt.Fprintf(&buf, "# %s\n\n", conversationTitle(conv))
+		fmt.Fprintf(&buf, "> 模型: %s · 创建时间: %s\n\n", conv.ModelName, formatExportTime(conv.Timestamp))
+
+		if systemPrompt := conversationSystemPrompt(conv); systemPrompt != "" {
+			fmt.Fprintf(&buf, "## System\n\n%s\n\n", systemPrompt)
+		}
+		for _, msg := range conv.Messages {
+			if strings.TrimSpace(msg.Content) == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, "## %s", roleLabel(msg.Role))
+			if msg.Timestamp > 0 {
+				fmt.Fprintf(&buf, " · %s", formatExportTime(msg.Timestamp))
+			}
+			buf.WriteString("\n\n")
+			if msg.Thinking != "" {
+				fmt.Fprintf(&buf, "<details>\n<summary>思考过程</summary>\n\n%s\n\n</details>\n\n", msg.Thinking)
+			}
+			fmt.Fprintf(&buf, "%s\n\n", msg.Content)
+		}
+	}
+	return buf.Bytes()
+}
+
+// conversationHTMLTemplate 独立 HTML 导出页面模板
+var conversationHTMLTemplate = template.Must(template.New("conversations").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; max-width: 880px; margin: 0 auto; padding: 24px; color: #1f2937; background: #f9fafb; }
+h1 { font-size: 22px; margin: 32px 0 4px; }
+.meta { color: #6b7280; font-size: 13px; margin-bottom: 16px; }
+.message { border-radius: 8px; padding: 12px 16px; margin: 12px 0; background: #fff; border: 1px solid #e5e7eb; }
+.message.user { background: #eff6ff; border-color: #bfdbfe; }
+.message.system { background: #fefce8; border-color: #fde68a; }
+.role { font-weight: 600; font-size: 13px; margin-bottom: 6px; }
+.role time { font-weight: 400; color: #9ca3af; margin-left: 8px; }
+.content { white-space: pre-wrap; word-wrap: break-word; line-height: 1.6; }
+.thinking { color: #6b7280; font-size: 13px; margin-bottom: 8px; }
+hr { border: none; border-top: 1px solid #e5e7eb; margin: 32px 0; }
+</style>
+</head>
+<body>
+{{range $i, $c := .Conversations}}{{if $i}}<hr>{{end}}
+<section>
+<h1>{{$c.Title}}</h1>
+<div class="meta">模型: {{$c.Model}} · 创建时间: {{$c.Time}}</div>
+{{range $c.Messages}}<div class="message {{.Role}}">
+<div class="role">{{.Label}}{{if .Time}}<time>{{.Time}}</time>{{end}}</div>
+{{if .Thinking}}<details class="thinking"><summary>思考过程</summary><div class="content">{{.Thinking}}</div></details>{{end}}
+<div class="content">{{.Content}}</div>
+</div>
+{{end}}</section>
+{{end}}
+</body>
+</html>
+`))
+
+// renderConversationsHTML 将对话渲染为可独立打开的 HTML 页面
+func renderConversationsHTML(convs []*types.Conversation) ([]byte, error) {
+	type htmlMessage struct {
+		Role, Label, Time, Content, Thinking string
+	}
+	type htmlConversation struct {
+		Title, Model, Time string
+		Messages           []htmlMessage
+	}
+
+	page := struct {
+		Title         string
+		Conversations []htmlConversation
+	}{Title: "对话导出"}
+	if len(convs) == 1 {
+		page.Title = conversationTitle(convs[0])
+	}
+
+	for _, conv := range convs {
+		hc := htmlConversation{
+			Title: conversationTitle(conv),
+			Model: conv.ModelName,
+			Time:  formatExportTime(conv.Timestamp),
+		}
+		if systemPrompt := conversationSystemPrompt(conv); systemPrompt != "" {
+			hc.Messages = append(hc.Messages, htmlMessage{Role: "system", Label: roleLabel("system"), Content: systemPrompt})
+		}
+		for _, msg := range conv.Messages {
+			if strings.TrimSpace(msg.Content) == "" {
+				continue
+			}
+			hm := htmlMessage{Role: msg.Role, Label: roleLabel(msg.Role), Content: msg.Content, Thinking: msg.Thinking}
+			if msg.Timestamp > 0 {
+				hm.Time = formatExportTime(msg.Timestamp)
+			}
+			hc.Messages = append(hc.Messages, hm)
+		}
+		page.Conversations = append(page.Conversations, hc)
+	}
+
+	var buf bytes.Buffer
+	if err := conversationHTMLTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("渲染HTML失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderConversationsJSONL 将对话渲染为每行一个样本的 chat 格式 JSONL
+// 开头的助手消息(如欢迎语)和空消息会被忽略, 没有助手回复的对话不会输出
+// 助手消息保留 tool_calls, 工具结果保留 tool_name, 使工具调用和结果成对出现
+func renderConversationsJSONL(convs []*types.Conversation) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, conv := range convs {
+		var record fineTuneRecord
+		if systemPrompt := conversationSystemPrompt(conv); systemPrompt != "" {
+			record.Messages = append(record.Messages, fineTuneMessage{Role: "system", Content: systemPrompt})
+		}
+
+		seenUser, hasAssistant := false, false
+		for _, msg := range conv.Messages {
+			// 只调用工具的助手消息和工具结果可以没有正文
+			if strings.TrimSpace(msg.Content) == "" && len(msg.ToolCalls) == 0 && msg.Role != "tool" {
+				continue
+			}
+			switch msg.Role {
+			case "user":
+				seenUser = true
+			case "assistant", "tool":
+				if !seenUser {
+					continue
+				}
+				hasAssistant = hasAssistant || msg.Role == "assistant"
+			}
+			record.Messages = append(record.Messages, fineTuneMessage{
+				Role:      msg.Role,
+				Content:   msg.Content,
+				ToolCalls: msg.ToolCalls,
+				ToolName:  msg.ToolName,
+			})
+		}
+		if !hasAssistant {
+			continue
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("序列化JSONL记录失败: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// conversationTitle 返回对话标题, 为空时使用默认值
+func conversationTitle(conv *types.Conversation) string {
+	if strings.TrimSpace(conv.Title) == "" {
+		return "未命名对话"
+	}
+	return conv.Title
+}
+
+// roleLabel 返回消息角色的显示名称
+func roleLabel(role string) string {
+	switch role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "system":
+		return "System"
+	default:
+		return role
+	}
+}
+
+// formatExportTime 将毫秒时间戳格式化为本地时间字符串
+func formatExportTime(ts int64) string {
+	if ts <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ts).Format(exportTimeLayout)
+}
+
+// exportFileExtension 返回导出格式对应的文件扩展名
+func exportFileExtension(format types.ExportFormat) string {
+	switch format {
+	case types.ExportFormatMarkdown:
+		return ".md"
+	case types.ExportFormatHtml:
+		return ".html"
+	case types.ExportFormatJsonl:
+		return ".jsonl"
+	default:
+		return ".json"
+	}
+}
+
+// sanitizeFileName 去除文件名中不允许的字符
+func sanitizeFileName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "conversation"
+	}
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+	return replacer.Replace(name)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"tools-ollama/types"
+)
+
+func TestRenderConversationsJSONLToolCalls(t *testing.T) {
+	call := types.ToolCall{Function: types.ToolCallFunction{Name: "calculator", Arguments: map[string]interface{}{"expression": "1+1"}}}
+	convs := []*types.Conversation{
+		{Messages: []types.Message{
+			{Role: "assistant", Content: "你好"},
+			{Role: "user", Content: "1+1=?"},
+			{Role: "assistant", ToolCalls: []types.ToolCall{call}},
+			{Role: "tool", ToolName: "calculator", Content: "2"},
+			{Role: "assistant", Content: "等于 2"},
+			{Role: "assistant", Content: "  "},
+		}},
+		{Messages: []types.Message{{Role: "user", Content: "没有回复"}}},
+	}
+
+	data, err := renderConversationsJSONL(convs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"messages":[` +
+		`{"role":"user","content":"1+1=?"},` +
+		`{"role":"assistant","content":"","tool_calls":[{"function":{"name":"calculator","arguments":{"expression":"1+1"}}}]},` +
+		`{"role":"tool","content":"2","tool_name":"calculator"},` +
+		`{"role":"assistant","content":"等于 2"}]}` + "\n"
+	if got := string(data); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, strings.TrimSpace(want))
+	}
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// --- ChatGPT conversations.json 导出格式 ---
+
+type chatGPTConversation struct {
+	Title            string                 `json:"title"`
+	CreateTime       float64                `json:"create_time"`
+	CurrentNode      string                 `json:"current_node"`
+	DefaultModelSlug string                 `json:"default_model_slug"`
+	Mapping          map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	ID       string          `json:"id"`
+	Parent   string          `json:"parent"`
+	Children []string        `json:"children"`
+	Message  *chatGPTMessage `json:"message"`
+}
+
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+	} `json:"author"`
+	CreateTime float64 `json:"create_time"`
+	Content    struct {
+		ContentType string            `json:"content_type"`
+		Parts       []json.RawMessage `json:"parts"`
+	} `json:"content"`
+	Metadata struct {
+		ModelSlug string `json:"model_slug"`
+	} `json:"metadata"`
+}
+
+// --- Open WebUI 导出格式 ---
+
+type openWebUIChat struct {
+	Title     string            `json:"title"`
+	CreatedAt float64           `json:"created_at"`
+	Chat      openWebUIChatBody `json:"chat"`
+}
+
+type openWebUIChatBody struct {
+	Title     string             `json:"title"`
+	Models    []string           `json:"models"`
+	Timestamp float64            `json:"timestamp"`
+	Messages  []openWebUIMessage `json:"messages"`
+	History   struct {
+		CurrentID string                      `json:"currentId"`
+		Messages  map[string]openWebUIMessage `json:"messages"`
+	} `json:"history"`
+}
+
+type openWebUIMessage struct {
+	ID        string  `json:"id"`
+	ParentID  string  `json:"parentId"`
+	Role      string  `json:"role"`
+	Content   string  `json:"content"`
+	Timestamp float64 `json:"timestamp"`
+	Model     string  `json:"model"`
+}
+
+// --- 通用 chat JSONL 格式 ---
+
+type chatJSONLRecord struct {
+	Title     string          `json:"title"`
+	Model     string          `json:"model"`
+	ModelName string          `json:"modelName"`
+	Messages  []types.Message `json:"messages"`
+}
+
+// SelectImportFile 弹出文件选择对话框, 返回选中的文件路径(用户取消时为空)
+func (cm *ChatManager) SelectImportFile() (string, error) {
+	path, err := runtime.OpenFileDialog(cm.ctx, runtime.OpenDialogOptions{
+		Title: "选择要导入的对话文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "JSON / JSONL", Pattern: "*.json;*.jsonl"},
+		},
+	})
+	if err != nil {
+		cm.logger.Error("打开文件对话框失败", "error", err)
+		return "", fmt.Errorf("打开文件对话框失败: %w", err)
+	}
+	return path, nil
+}
+
+// ImportConversationsFromFile 从文件导入对话
+func (cm *ChatManager) ImportConversationsFromFile(path string, source types.ImportSource, dryRun bool) (types.ImportReport, error) {
+	cm.logger.Info("从文件导入对话", "path", path, "source", source, "dryRun", dryRun)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		cm.logger.Error("读取导入文件失败", "path", path, "error", err)
+		return types.ImportReport{}, fmt.Errorf("读取导入文件失败: %w", err)
+	}
+	return cm.ImportConversations(string(data), source, dryRun)
+}
+
+// ImportConversations 将其他工具导出的对话数据映射为 types.Conversation 并保存
+// 通过内容哈希去重, dryRun 为 true 时只返回报告而不写入存储
+func (cm *ChatManager) ImportConversations(data string, source types.ImportSource, dryRun bool) (types.ImportReport, error) {
+	raw := bytes.TrimSpace([]byte(data))
+	if len(raw) == 0 {
+		return types.ImportReport{}, fmt.Errorf("导入内容为空")
+	}
+
+	if source == "" || source == types.ImportSourceAuto {
+		source = detectImportSource(raw)
+		cm.logger.Debug("自动识别导入来源", "source", source)
+	}
+
+	var convs []*types.Conversation
+	var err error
+	switch source {
+	case types.ImportSourceChatGPT:
+		convs, err = parseChatGPTExport(raw)
+	case types.ImportSourceOpenWebUI:
+		convs, err = parseOpenWebUIExport(raw)
+	case types.ImportSourceJson:
+		convs, err = parseConversationJSON(raw)
+	case types.ImportSourceJsonl:
+		convs, err = parseChatJSONL(raw)
+	default:
+		return types.ImportReport{}, fmt.Errorf("不支持的导入来源: %s", source)
+	}
+	if err != nil {
+		cm.logger.Error("解析导入数据失败", "source", source, "error", err)
+		return types.ImportReport{}, err
+	}
+	sortConversationsByTime(convs)
+
+	existing, err := cm.ListConversations()
+	if err != nil {
+		return types.ImportReport{}, err
+	}
+	seen := make(map[string]string, len(existing))
+	for _, conv := range existing {
+		seen[conversationContentHash(conv.Messages)] = conv.ID
+	}
+
+	report := types.ImportReport{Source: source, DryRun: dryRun, Total: len(convs)}
+	for _, conv := range convs {
+		item := types.ImportItem{
+			Title:        conv.Title,
+			MessageCount: len(conv.Messages),
+		}
+		if len(conv.Messages) == 0 {
+			item.Status = types.ImportItemStatusInvalid
+			item.Reason = "对话不包含任何消息"
+			report.Invalid++
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		item.ContentHash = conversationContentHash(conv.Messages)
+		if id, ok := seen[item.ContentHash]; ok {
+			item.Status = types.ImportItemStatusDuplicate
+			item.ConversationID = id
+			report.Duplicates++
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		item.Status = types.ImportItemStatusNew
+		if cleared := sanitizeAttachmentPaths(conv); cleared > 0 {
+			cm.logger.Warn("导入的对话包含附件目录之外的附件路径, 已忽略", "title", conv.Title, "count", cleared)
+		}
+		conv.ID = GenerateUniqueID()
+		if conv.Timestamp == 0 {
+			conv.Timestamp = GetCurrentTimestamp()
+		}
+		if !dryRun {
+			if _, err := cm.SaveConversation(conv); err != nil {
+				item.Status = types.ImportItemStatusInvalid
+				item.Reason = err.Error()
+				report.Invalid++
+				report.Items = append(report.Items, item)
+				continue
+			}
+			item.ConversationID = conv.ID
+		}
+		seen[item.ContentHash] = conv.ID
+		report.Imported++
+		report.Items = append(report.Items, item)
+	}
+
+	cm.logger.Info("对话导入完成", "source", source, "dryRun", dryRun, "total", report.Total,
+		"imported", report.Imported, "duplicates", report.Duplicates, "invalid", report.Invalid)
+	return report, nil
+}
+
+// detectImportSource 根据数据结构猜测导入来源
+func detectImportSource(raw []byte) types.ImportSource {
+	if raw[0] == '[' {
+		var probe []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &probe); err == nil && len(probe) > 0 {
+			if _, ok := probe[0]["mapping"]; ok {
+				return types.ImportSourceChatGPT
+			}
+			if _, ok := probe[0]["chat"]; ok {
+				return types.ImportSourceOpenWebUI
+			}
+			return types.ImportSourceJson
+		}
+	}
+	if raw[0] == '{' {
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &probe); err == nil {
+			if _, ok := probe["mapping"]; ok {
+				return types.ImportSourceChatGPT
+			}
+			if _, ok := probe["chat"]; ok {
+				return types.ImportSourceOpenWebUI
+			}
+			return types.ImportSourceJson
+		}
+	}
+	return types.ImportSourceJsonl
+}
+
+// decodeOneOrMany 解析单个对象或对象数组
+func decodeOneOrMany[T any](raw []byte) ([]T, error) {
+	if raw[0] == '[' {
+		var items []T
+		err := json.Unmarshal(raw, &items)
+		return items, err
+	}
+	var item T
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return []T{item}, nil
+}
+
+// parseChatGPTExport 解析 ChatGPT 的 conversations.json 导出
+// ChatGPT 以消息树保存对话, 这里从 current_node 回溯到根节点以得到当前分支
+func parseChatGPTExport(raw []byte) ([]*types.Conversation, error) {
+	items, err := decodeOneOrMany[chatGPTConversation](raw)
+	if err != nil {
+		return nil, fmt.Errorf("解析ChatGPT导出失败: %w", err)
+	}
+
+	convs := make([]*types.Conversation, 0, len(items))
+	for _, item := range items {
+		conv := &types.Conversation{
+			Title:     item.Title,
+			ModelName: item.DefaultModelSlug,
+			Timestamp: toMillis(item.CreateTime),
+		}
+
+		nodeID := item.CurrentNode
+		if nodeID == "" {
+			nodeID = chatGPTLeafNode(item.Mapping)
+		}
+		var branch []*chatGPTMessage
+		for visited := 0; nodeID != "" && visited <= len(item.Mapping); visited++ {
+			node, ok := item.Mapping[nodeID]
+			if !ok {
+				break
+			}
+			if node.Message != nil {
+				branch = append(branch, node.Message)
+			}
+			nodeID = node.Parent
+		}
+		ReverseSlice(branch)
+
+		for _, msg := range branch {
+			role := msg.Author.Role
+			if role != "user" && role != "assistant" && role != "system" {
+				continue
+			}
+			content := chatGPTTextParts(msg)
+			if strings.TrimSpace(content) == "" {
+				continue
+			}
+			if role == "assistant" && conv.ModelName == "" {
+				conv.ModelName = msg.Metadata.ModelSlug
+			}
+			conv.Messages = append(conv.Messages, types.Message{
+				Role:      role,
+				Content:   content,
+				Timestamp: toMillis(msg.CreateTime),
+			})
+		}
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
+
+// chatGPTLeafNode 在缺少 current_node 时选取没有子节点的最后一个节点
+func chatGPTLeafNode(mapping map[string]chatGPTNode) string {
+	var leaf string
+	var latest float64 = -1
+	for id, node := range mapping {
+		if len(node.Children) > 0 {
+			continue
+		}
+		var ts float64
+		if node.Message != nil {
+			ts = node.Message.CreateTime
+		}
+		if ts > latest {
+			leaf, latest = id, ts
+		}
+	}
+	return leaf
+}
+
+// chatGPTTextParts 拼接消息中的文本片段, 忽略图片等非文本内容
+func chatGPTTextParts(msg *chatGPTMessage) string {
+	var texts []string
+	for _, part := range msg.Content.Parts {
+		var text string
+		if err := json.Unmarshal(part, &text); err == nil {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// parseOpenWebUIExport 解析 Open WebUI 的对话导出
+func parseOpenWebUIExport(raw []byte) ([]*types.Conversation, error) {
+	items, err := decodeOneOrMany[openWebUIChat](raw)
+	if err != nil {
+		return nil, fmt.Errorf("解析Open WebUI导出失败: %w", err)
+	}
+
+	convs := make([]*types.Conversation, 0, len(items))
+	for _, item := range items {
+		conv := &types.Conversation{
+			Title:     item.Title,
+			Timestamp: toMillis(item.CreatedAt),
+		}
+		if conv.Title == "" {
+			conv.Title = item.Chat.Title
+		}
+		if conv.Timestamp == 0 {
+			conv.Timestamp = toMillis(item.Chat.Timestamp)
+		}
+		if len(item.Chat.Models) > 0 {
+			conv.ModelName = item.Chat.Models[0]
+		}
+
+		messages := item.Chat.Messages
+		if history := item.Chat.History; history.CurrentID != "" && len(history.Messages) > 0 {
+			// history 中保存了完整的消息树, 优先使用当前分支
+			messages = messages[:0:0]
+			for id, visited := history.CurrentID, 0; id != "" && visited <= len(history.Messages); visited++ {
+				msg, ok := history.Messages[id]
+				if !ok {
+					break
+				}
+				messages = append(messages, msg)
+				id = msg.ParentID
+			}
+			ReverseSlice(messages)
+		}
+
+		for _, msg := range messages {
+			if strings.TrimSpace(msg.Content) == "" {
+				continue
+			}
+			if conv.ModelName == "" && msg.Model != "" {
+				conv.ModelName = msg.Model
+			}
+			conv.Messages = append(conv.Messages, types.Message{
+				Role:      msg.Role,
+				Content:   msg.Content,
+				Timestamp: toMillis(msg.Timestamp),
+			})
+		}
+		convs = append(convs, conv)
+	}
+	return convs, nil
+}
+
+// parseConversationJSON 解析本应用导出的 types.Conversation JSON (单个或数组)
+func parseConversationJSON(raw []byte) ([]*types.Conversation, error) {
+	items, err := decodeOneOrMany[types.Conversation](raw)
+	if err != nil {
+		return nil, fmt.Errorf("解析对话JSON失败: %w", err)
+	}
+	convs := make([]*types.Conversation, 0, len(items))
+	for i := range items {
+		convs = append(convs, &items[i])
+	}
+	return convs, nil
+}
+
+// parseChatJSONL 解析每行一个 {"messages": [...]} 记录的通用 chat JSONL
+func parseChatJSONL(raw []byte) ([]*types.Conversation, error) {
+	var convs []*types.Conversation
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var record chatJSONLRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("解析JSONL第 %d 行失败: %w", lineNo, err)
+		}
+
+		conv := &types.Conversation{
+			Title:     record.Title,
+			ModelName: record.ModelName,
+		}
+		if conv.ModelName == "" {
+			conv.ModelName = record.Model
+		}
+		for _, msg := range record.Messages {
+			if strings.TrimSpace(msg.Content) != "" {
+				conv.Messages = append(conv.Messages, msg)
+			}
+		}
+		if conv.Title == "" {
+			conv.Title = titleFromMessages(conv.Messages)
+		}
+		convs = append(convs, conv)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取JSONL失败: %w", err)
+	}
+	return convs, nil
+}
+
+// conversationContentHash 基于消息角色和内容计算对话哈希, 用于导入去重
+func conversationContentHash(messages []types.Message) string {
+	h := sha256.New()
+	for _, msg := range messages {
+		content := strings.TrimSpace(msg.Content)
+		if content == "" {
+			continue
+		}
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(content))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// titleFromMessages 使用第一条用户消息生成对话标题
+func titleFromMessages(messages []types.Message) string {
+	for _, msg := range messages {
+		if msg.Role != "user" {
+			continue
+		}
+		title := []rune(strings.TrimSpace(strings.SplitN(msg.Content, "\n", 2)[0]))
+		if len(title) > 30 {
+			title = append(title[:30], '…')
+		}
+		return string(title)
+	}
+	return ""
+}
+
+// toMillis 将秒或毫秒时间戳统一转换为毫秒
+func toMillis(ts float64) int64 {
+	if ts <= 0 {
+		return 0
+	}
+	if ts < 1e12 {
+		return int64(ts * 1000)
+	}
+	return int64(ts)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"tools-ollama/types"
 
@@ -12,16 +13,20 @@ import (
 
 // ChatManager 聊天管理器
 type ChatManager struct {
-	ctx        context.Context
-	store      *duolasdk.AppStore
-	aiProvider AIProvider
-	logger     *core.AppLog
+	ctx           context.Context
+	store         *duolasdk.AppStore
+	aiProvider    AIProvider
+	toolRegistry  *ToolRegistry
+	knowledgeBase *KnowledgeBase
+	usageRecorder PromptUsageRecorder
+	logger        *core.AppLog
 }
 
 // AIProvider 定义了AI聊天能力的接口
 type AIProvider interface {
-	Chat(model string, messages []core.Message) (string, error)
-	ChatStream(model string, messages []core.Message, callback func(string)) error
+	Chat(req OllamaChatRequest) (ChatResult, error)
+	ChatStream(req OllamaChatRequest, callback func(ChatChunk)) (ChatResult, error)
+	Generate(req OllamaGenerateRequest) (string, error)
 }
 
 // NewChatManager 创建聊天管理器实例
@@ -44,6 +49,21 @@ func (cm *ChatManager) SetAIProvider(provider AIProvider) {
 	cm.aiProvider = provider
 }
 
+// SetToolRegistry 设置工具注册表
+func (cm *ChatManager) SetToolRegistry(registry *ToolRegistry) {
+	cm.toolRegistry = registry
+}
+
+// SetKnowledgeBase 设置知识库
+func (cm *ChatManager) SetKnowledgeBase(kb *KnowledgeBase) {
+	cm.knowledgeBase = kb
+}
+
+// SetPromptUsageRecorder 设置提示词使用记录器
+func (cm *ChatManager) SetPromptUsageRecorder(recorder PromptUsageRecorder) {
+	cm.usageRecorder = recorder
+}
+
 // ListConversations 获取所有已保存的对话列表，按时间倒序排列
 func (cm *ChatManager) ListConversations() ([]*types.Conversation, error) {
 	cm.logger.Debug("获取所有对话列表")
@@ -71,6 +91,11 @@ func (cm *ChatManager) ListConversations() ([]*types.Conversation, error) {
 
 // SaveConversation 创建或更新一个对话
 func (cm *ChatManager) SaveConversation(conv *types.Conversation) (*types.Conversation, error) {
+	if err := validateConversationSettings(conv); err != nil {
+		cm.logger.Warn("对话设置校验失败", "id", conv.ID, "error", err)
+		return nil, err
+	}
+
 	if conv.ID == "" {
 		conv.ID = GenerateUniqueID()
 		conv.Timestamp = GetCurrentTimestamp()
@@ -114,25 +139,115 @@ func (cm *ChatManager) GetConversation(id string) (*types.Conversation, error) {
 // DeleteConversation 删除指定ID的对话
 func (cm *ChatManager) DeleteConversation(id string) error {
 	cm.logger.Info("删除对话", "id", id)
+	conv, getErr := cm.GetConversation(id)
 	if err := cm.store.HDel("conversations", id); err != nil {
 		cm.logger.Error("从存储删除对话失败", "id", id, "error", err)
 		return fmt.Errorf("删除对话失败: %w", err)
 	}
+	if getErr == nil {
+		cm.removeConversationAttachments(conv)
+	}
+	return nil
+}
+
+// DetachCollection 把已删除的知识库集合从所有对话的关联中移除
+func (cm *ChatManager) DetachCollection(collectionID string) error {
+	conversations, err := cm.ListConversations()
+	if err != nil {
+		return err
+	}
+	for _, conv := range conversations {
+		kept := conv.Collections[:0]
+		for _, id := range conv.Collections {
+			if id != collectionID {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == len(conv.Collections) {
+			continue
+		}
+		conv.Collections = kept
+		if _, err := cm.SaveConversation(conv); err != nil {
+			return fmt.Errorf("移除对话关联的知识库失败: %w", err)
+		}
+		cm.logger.Info("已从对话中移除知识库", "conversationID", conv.ID, "collectionID", collectionID)
+	}
 	return nil
 }
 
 // ChatMessage 发送聊天消息到Ollama API
 func (cm *ChatManager) ChatMessage(modelName string, messages []types.Message, stream bool) (string, error) {
-	cm.logger.Debug("收到聊天消息请求", "model", modelName, "messageCount", len(messages), "stream", stream)
+	resp, err := cm.SendChat(types.ChatRequest{
+		ModelName: modelName,
+		Messages:  messages,
+		Stream:    stream,
+	})
+	return resp.Content, err
+}
+
+// SendChat 发送聊天请求, 启用工具时会循环执行模型发起的工具调用直到得到最终回复
+// 流式模式下立即返回, 正文和思考过程分别通过 chat_stream_chunk / chat_stream_thinking 事件推送,
+// 工具调用与结果消息通过 chat_tool_message 事件推送, 关联知识库时检索到的引用来源通过 chat_citations 事件推送
+func (cm *ChatManager) SendChat(req types.ChatRequest) (types.ChatResponse, error) {
+	cm.logger.Debug("收到聊天消息请求", "model", req.ModelName, "messageCount", len(req.Messages), "stream", req.Stream, "tools", req.Tools)
 
 	if cm.aiProvider == nil {
 		cm.logger.Error("AI provider未设置")
-		return "", fmt.Errorf("AI provider not set")
+		return types.ChatResponse{}, fmt.Errorf("AI provider not set")
+	}
+
+	chatMessages, err := ToOllamaChatMessages(req.Messages)
+	if err != nil {
+		cm.logger.Error("转换聊天消息失败", "error", err)
+		return types.ChatResponse{}, err
+	}
+
+	baseReq := OllamaChatRequest{
+		Model:    req.ModelName,
+		Messages: chatMessages,
+		Think:    req.Think,
+	}
+	toolNames := req.Tools
+	collections := req.Collections
+	if req.ConversationID != "" {
+		if conv, err := cm.GetConversation(req.ConversationID); err == nil {
+			if len(toolNames) == 0 {
+				toolNames = conv.Tools
+			}
+			if len(collections) == 0 {
+				collections = conv.Collections
+			}
+			if baseReq.Model == "" {
+				baseReq.Model = conv.ModelName
+			}
+			baseReq.ServerID = conv.ServerID
+			if err := applyConversationSettings(conv, &baseReq); err != nil {
+				cm.logger.Error("应用对话设置失败", "id", conv.ID, "error", err)
+				return types.ChatResponse{}, err
+			}
+			cm.recordPromptUsage(conv, baseReq)
+		}
+	}
+
+	var citations []types.KnowledgeHit
+	if len(collections) > 0 {
+		if citations, err = cm.retrieveKnowledge(collections, &baseReq); err != nil {
+			cm.logger.Error("知识库检索失败", "collections", collections, "error", err)
+			return types.ChatResponse{}, err
+		}
 	}
 
-	coreMessages := ToCoreMessages(messages)
+	var tools []types.ToolDefinition
+	if len(toolNames) > 0 {
+		if cm.toolRegistry == nil {
+			return types.ChatResponse{}, fmt.Errorf("工具注册表未设置")
+		}
+		if tools, err = cm.toolRegistry.Resolve(toolNames); err != nil {
+			return types.ChatResponse{}, err
+		}
+	}
 
-	if stream {
+	if req.Stream {
 		cm.logger.Debug("使用流式传输")
 		go func() {
 			defer func() {
@@ -142,23 +257,190 @@ func (cm *ChatManager) ChatMessage(modelName string, messages []types.Message, s
 				}
 				runtime.EventsEmit(cm.ctx, "chat_stream_done")
 			}()
-			err := cm.aiProvider.ChatStream(modelName, coreMessages, func(content string) {
-				runtime.EventsEmit(cm.ctx, "chat_stream_chunk", content)
-			})
-			if err != nil {
+			if len(citations) > 0 {
+				runtime.EventsEmit(cm.ctx, "chat_citations", citations)
+			}
+			if _, err := cm.runChatLoop(baseReq, tools, true); err != nil {
 				cm.logger.Error("流式聊天失败", "error", err)
 				runtime.EventsEmit(cm.ctx, "chat_stream_error", err.Error())
 			}
 		}()
-		return "", nil // For stream, the main function returns immediately
-	} else {
-		cm.logger.Debug("使用阻塞式传输")
-		result, err := cm.aiProvider.Chat(modelName, coreMessages)
+		return types.ChatResponse{}, nil // For stream, the main function returns immediately
+	}
+
+	cm.logger.Debug("使用阻塞式传输")
+	resp, err := cm.runChatLoop(baseReq, tools, false)
+	if err != nil {
+		cm.logger.Error("阻塞式聊天失败", "error", err)
+		return types.ChatResponse{}, err
+	}
+	resp.Citations = citations
+	cm.logger.Debug("阻塞式传输成功", "resultLength", len(resp.Content), "toolMessages", len(resp.Messages))
+	return resp, nil
+}
+
+// recordPromptUsage 对话使用了已保存的提示词作为系统提示词时记录使用关联
+func (cm *ChatManager) recordPromptUsage(conv *types.Conversation, req OllamaChatRequest) {
+	if cm.usageRecorder == nil {
+		return
+	}
+	prompt := ParseConversationSystemPrompt(conv)
+	if prompt == nil || prompt.ID == "" {
+		return
+	}
+	cm.usageRecorder.RecordPromptUsage(types.PromptUsage{
+		PromptID:          prompt.ID,
+		Version:           prompt.Version,
+		Source:            types.PromptUsageSourceConversation,
+		ConversationID:    conv.ID,
+		ConversationTitle: conv.Title,
+		ServerID:          req.ServerID,
+		Model:             req.Model,
+	})
+}
+
+// retrieveKnowledge 以最后一条用户消息为问题检索知识库, 并把参考资料插入到该消息之前
+// 返回的片段顺序与参考资料中的序号一致, 用于前端展示引用来源
+func (cm *ChatManager) retrieveKnowledge(collections []string, req *OllamaChatRequest) ([]types.KnowledgeHit, error) {
+	if cm.knowledgeBase == nil {
+		return nil, fmt.Errorf("知识库未设置")
+	}
+
+	lastUser := -1
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			lastUser = i
+			break
+		}
+	}
+	if lastUser < 0 {
+		return nil, nil
+	}
+
+	hits, err := cm.knowledgeBase.Search(collections, req.Messages[lastUser].Content, defaultRetrievalTopK)
+	if err != nil || len(hits) == 0 {
+		return nil, err
+	}
+
+	contextMsg := OllamaChatMessage{Role: "system", Content: BuildKnowledgeContext(hits)}
+	messages := make([]OllamaChatMessage, 0, len(req.Messages)+1)
+	messages = append(messages, req.Messages[:lastUser]...)
+	messages = append(messages, contextMsg)
+	req.Messages = append(messages, req.Messages[lastUser:]...)
+	return hits, nil
+}
+
+// runChatLoop 调用模型, 若模型返回工具调用则执行工具并把结果回传, 直到模型给出最终回复
+// base 中携带模型、初始消息以及 think / options 等每轮都需要的字段
+func (cm *ChatManager) runChatLoop(base OllamaChatRequest, tools []types.ToolDefinition, stream bool) (types.ChatResponse, error) {
+	var resp types.ChatResponse
+	maxRounds := 0
+	if len(tools) > 0 {
+		maxRounds = cm.toolRegistry.GetSettings().MaxToolRounds
+	}
+
+	messages := base.Messages
+	for round := 0; ; round++ {
+		req := base
+		req.Messages = messages
+		req.Tools = ToOllamaTools(tools)
+
+		var result ChatResult
+		var err error
+		if stream {
+			result, err = cm.aiProvider.ChatStream(req, func(chunk ChatChunk) {
+				if chunk.Thinking != "" {
+					runtime.EventsEmit(cm.ctx, "chat_stream_thinking", chunk.Thinking)
+				}
+				if chunk.Content != "" {
+					runtime.EventsEmit(cm.ctx, "chat_stream_chunk", chunk.Content)
+				}
+			})
+		} else {
+			result, err = cm.aiProvider.Chat(req)
+		}
 		if err != nil {
-			cm.logger.Error("阻塞式聊天失败", "error", err)
-			return "", err
+			return types.ChatResponse{}, err
 		}
-		cm.logger.Debug("阻塞式传输成功", "resultLength", len(result))
-		return result, nil
+
+		if len(result.ToolCalls) == 0 || len(tools) == 0 {
+			resp.Content = result.Content
+			resp.Thinking = result.Thinking
+			return resp, nil
+		}
+		if round >= maxRounds {
+			return types.ChatResponse{}, fmt.Errorf("工具调用超过最大轮数 %d", maxRounds)
+		}
+
+		assistantMsg := types.Message{
+			Role:      "assistant",
+			Content:   result.Content,
+			Thinking:  result.Thinking,
+			Timestamp: GetCurrentTimestamp(),
+			ToolCalls: result.ToolCalls,
+		}
+		cm.recordToolMessage(&resp, assistantMsg)
+		messages = append(messages, OllamaChatMessage{Role: "assistant", Content: result.Content, ToolCalls: result.ToolCalls})
+
+		for _, call := range result.ToolCalls {
+			output := cm.executeToolCall(tools, call)
+			toolMsg := types.Message{
+				Role:      "tool",
+				Content:   output,
+				Timestamp: GetCurrentTimestamp(),
+				ToolName:  call.Function.Name,
+			}
+			cm.recordToolMessage(&resp, toolMsg)
+			messages = append(messages, OllamaChatMessage{Role: "tool", Content: output, ToolName: call.Function.Name})
+		}
+	}
+}
+
+// recordToolMessage 记录工具相关消息并通知前端, 以便写入对话历史
+func (cm *ChatManager) recordToolMessage(resp *types.ChatResponse, msg types.Message) {
+	resp.Messages = append(resp.Messages, msg)
+	runtime.EventsEmit(cm.ctx, "chat_tool_message", msg)
+}
+
+// executeToolCall 执行单个工具调用, 错误以文本形式返回给模型以便其自行调整
+func (cm *ChatManager) executeToolCall(tools []types.ToolDefinition, call types.ToolCall) string {
+	var tool *types.ToolDefinition
+	for i := range tools {
+		if tools[i].Name == call.Function.Name {
+			tool = &tools[i]
+			break
+		}
+	}
+	if tool == nil {
+		return fmt.Sprintf("错误: 工具 %s 未启用", call.Function.Name)
+	}
+
+	if tool.SideEffect && !cm.confirmToolCall(call) {
+		cm.logger.Info("用户拒绝执行工具", "name", tool.Name)
+		return "错误: 用户拒绝执行该工具"
+	}
+
+	output, err := cm.toolRegistry.Execute(*tool, call)
+	if err != nil {
+		return "错误: " + err.Error()
+	}
+	return output
+}
+
+// confirmToolCall 弹出对话框请求用户确认执行有副作用的工具
+func (cm *ChatManager) confirmToolCall(call types.ToolCall) bool {
+	args, _ := json.MarshalIndent(call.Function.Arguments, "", "  ")
+	result, err := runtime.MessageDialog(cm.ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "确认执行工具",
+		Message:       fmt.Sprintf("模型请求执行工具 %s, 参数:\n%s\n\n是否允许?", call.Function.Name, string(args)),
+		Buttons:       []string{"Yes", "No"},
+		DefaultButton: "No",
+		CancelButton:  "No",
+	})
+	if err != nil {
+		cm.logger.Error("弹出确认对话框失败", "error", err)
+		return false
 	}
+	return result == "Yes"
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"tools-ollama/types"
+)
+
+// ParseConversationModelParams 解析对话中保存的模型参数 JSON, 未设置时返回空参数
+func ParseConversationModelParams(conv *types.Conversation) (types.ConversationModelParams, error) {
+	var params types.ConversationModelParams
+	if strings.TrimSpace(conv.ModelParams) == "" {
+		return params, nil
+	}
+	if err := json.Unmarshal([]byte(conv.ModelParams), &params); err != nil {
+		return params, fmt.Errorf("模型参数格式错误: %w", err)
+	}
+	return params, nil
+}
+
+// ParseConversationSystemPrompt 解析对话中保存的系统提示词 JSON, 未设置时返回 nil
+// 不是提示词 JSON 时按纯文本处理, 兼容直接填写系统提示词的旧对话和导入数据
+func ParseConversationSystemPrompt(conv *types.Conversation) *types.Prompt {
+	if strings.TrimSpace(conv.SystemPrompt) == "" {
+		return nil
+	}
+	var prompt types.Prompt
+	if err := json.Unmarshal([]byte(conv.SystemPrompt), &prompt); err != nil {
+		return &types.Prompt{Content: conv.SystemPrompt}
+	}
+	return &prompt
+}
+
+// ValidateConversationModelParams 校验对话级模型参数的取值范围
+func ValidateConversationModelParams(params types.ConversationModelParams) error {
+	if params.Temperature != nil && (*params.Temperature < 0 || *params.Temperature > 2) {
+		return fmt.Errorf("temperature 必须在 0 到 2 之间")
+	}
+	if params.TopP != nil && (*params.TopP < 0 || *params.TopP > 1) {
+		return fmt.Errorf("topP 必须在 0 到 1 之间")
+	}
+	if params.TopK != nil && *params.TopK < 0 {
+		return fmt.Errorf("topK 不能为负数")
+	}
+	if params.Context != nil && *params.Context < 0 {
+		return fmt.Errorf("context 不能为负数")
+	}
+	if params.NumPredict != nil && *params.NumPredict < -2 {
+		return fmt.Errorf("numPredict 不能小于 -2")
+	}
+	if params.RepeatPenalty != nil && *params.RepeatPenalty < 0 {
+		return fmt.Errorf("repeatPenalty 不能为负数")
+	}
+	switch params.OutputMode {
+	case "", "stream", "blocking":
+	default:
+		return fmt.Errorf("不支持的输出模式: %s", params.OutputMode)
+	}
+	return nil
+}
+
+// ToOllamaOptions 将对话级模型参数转换为 Ollama 的 options, 只包含已设置的字段
+func ToOllamaOptions(params types.ConversationModelParams) map[string]interface{} {
+	options := make(map[string]interface{})
+	if params.Temperature != nil {
+		options["temperature"] = *params.Temperature
+	}
+	if params.TopP != nil {
+		options["top_p"] = *params.TopP
+	}
+	if params.TopK != nil && *params.TopK > 0 {
+		options["top_k"] = *params.TopK
+	}
+	if params.Context != nil && *params.Context > 0 {
+		options["num_ctx"] = *params.Context
+	}
+	if params.NumPredict != nil && *params.NumPredict != 0 {
+		options["num_predict"] = *params.NumPredict
+	}
+	if params.RepeatPenalty != nil && *params.RepeatPenalty > 0 {
+		options["repeat_penalty"] = *params.RepeatPenalty
+	}
+	if params.Seed != nil {
+		options["seed"] = *params.Seed
+	}
+	if len(params.Stop) > 0 {
+		options["stop"] = params.Stop
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// validateConversationSettings 保存对话前校验模型参数
+func validateConversationSettings(conv *types.Conversation) error {
+	params, err := ParseConversationModelParams(conv)
+	if err != nil {
+		return err
+	}
+	return ValidateConversationModelParams(params)
+}
+
+// applyConversationSettings 把对话的系统提示词和模型参数应用到请求上
+// 消息列表已以 system 消息开头时不再重复注入系统提示词
+func applyConversationSettings(conv *types.Conversation, req *OllamaChatRequest) error {
+	params, err := ParseConversationModelParams(conv)
+	if err != nil {
+		return err
+	}
+	req.Options = ToOllamaOptions(params)
+
+	prompt := ParseConversationSystemPrompt(conv)
+	if prompt == nil || strings.TrimSpace(prompt.Content) == "" {
+		return nil
+	}
+	if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+		return nil
+	}
+	req.Messages = append([]OllamaChatMessage{{Role: "system", Content: prompt.Content}}, req.Messages...)
+	return nil
+}
+
+// conversationSystemPrompt 从对话的 SystemPrompt 字段中取出提示词正文
+func conversationSystemPrompt(conv *types.Conversation) string {
+	prompt := ParseConversationSystemPrompt(conv)
+	if prompt == nil {
+		return ""
+	}
+	return prompt.Content
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"tools-ollama/types"
+)
+
+const (
+	// defaultStructuredRetries 未指定时的默认重试次数
+	defaultStructuredRetries = 2
+	// maxStructuredRetries 重试次数上限
+	maxStructuredRetries = 10
+)
+
+// GenerateStructured 通过 Ollama 的 format 字段传入 JSON Schema 约束模型输出
+// 返回内容在 Go 侧再次按 schema 校验, 失败时把校验错误反馈给模型重试
+func (cm *ChatManager) GenerateStructured(req types.StructuredRequest) (types.StructuredResult, error) {
+	cm.logger.Debug("开始结构化输出", "model", req.ModelName, "mode", req.Mode, "maxRetries", req.MaxRetries)
+
+	if cm.aiProvider == nil {
+		return types.StructuredResult{}, fmt.Errorf("AI provider not set")
+	}
+	if req.ModelName == "" {
+		return types.StructuredResult{}, fmt.Errorf("模型名称不能为空")
+	}
+	schema, err := ParseJSONSchema(req.Schema)
+	if err != nil {
+		return types.StructuredResult{}, err
+	}
+
+	retries := req.MaxRetries
+	if retries <= 0 {
+		retries = defaultStructuredRetries
+	}
+	retries = min(retries, maxStructuredRetries)
+
+	var chatMessages []OllamaChatMessage
+	switch req.Mode {
+	case "", "chat":
+		if len(req.Messages) == 0 {
+			return types.StructuredResult{}, fmt.Errorf("chat 模式需要至少一条消息")
+		}
+		if chatMessages, err = ToOllamaChatMessages(req.Messages); err != nil {
+			return types.StructuredResult{}, err
+		}
+	case "generate":
+		if strings.TrimSpace(req.Prompt) == "" {
+			return types.StructuredResult{}, fmt.Errorf("generate 模式需要提示词")
+		}
+	default:
+		return types.StructuredResult{}, fmt.Errorf("不支持的模式: %s", req.Mode)
+	}
+
+	var result types.StructuredResult
+	prompt := req.Prompt
+	for attempt := 0; attempt <= retries; attempt++ {
+		result.Attempts = attempt + 1
+
+		var raw string
+		if req.Mode == "generate" {
+			raw, err = cm.aiProvider.Generate(OllamaGenerateRequest{
+				Model:  req.ModelName,
+				Prompt: prompt,
+				System: req.System,
+				Format: schema,
+			})
+		} else {
+			var chatResult ChatResult
+			chatResult, err = cm.aiProvider.Chat(OllamaChatRequest{
+				Model:    req.ModelName,
+				Messages: chatMessages,
+				Format:   schema,
+			})
+			raw = chatResult.Content
+		}
+		if err != nil {
+			cm.logger.Error("结构化输出请求失败", "attempt", result.Attempts, "error", err)
+			return result, err
+		}
+		result.Raw = raw
+
+		data, validationErr := parseStructuredOutput(raw, schema)
+		if validationErr == nil {
+			result.Data = data
+			result.Valid = true
+			cm.logger.Info("结构化输出校验通过", "model", req.ModelName, "attempts", result.Attempts)
+			return result, nil
+		}
+
+		cm.logger.Warn("结构化输出校验失败", "attempt", result.Attempts, "error", validationErr)
+		result.Errors = append(result.Errors, validationErr.Error())
+
+		feedback := fmt.Sprintf("你上一次的输出没有通过 JSON Schema 校验, 错误如下:\n%s\n\n请修正后只输出一个符合 schema 的 JSON, 不要包含任何解释。", validationErr)
+		if req.Mode == "generate" {
+			prompt = fmt.Sprintf("%s\n\n上一次的输出:\n%s\n\n%s", req.Prompt, raw, feedback)
+		} else {
+			chatMessages = append(chatMessages,
+				OllamaChatMessage{Role: "assistant", Content: raw},
+				OllamaChatMessage{Role: "user", Content: feedback},
+			)
+		}
+	}
+
+	cm.logger.Warn("结构化输出在重试后仍未通过校验", "model", req.ModelName, "attempts", result.Attempts)
+	return result, nil
+}
+
+// parseStructuredOutput 解析模型输出的 JSON 并按 schema 校验
+// 兼容模型把 JSON 包在 ```json 代码块中的情况
+func parseStructuredOutput(raw string, schema map[string]interface{}) (interface{}, error) {
+	text := stripCodeFence(raw)
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return nil, fmt.Errorf("输出不是合法的 JSON: %w", err)
+	}
+	if err := ValidateJSONSchema(schema, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/16chusi/duolasdk/core"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ollamaGenerateResponse /api/generate 响应 (流式时为单个分块)
+type ollamaGenerateResponse struct {
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason"`
+	Context         []int  `json:"context"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	EvalDuration    int64  `json:"eval_duration"`
+	TotalDuration   int64  `json:"total_duration"`
+	Error           string `json:"error"`
+}
+
+// Complete 在补全工作区中调用 /api/generate, 支持 raw 模式、自定义模板、FIM 后缀和上下文延续
+// 流式模式下立即返回, 内容通过 completion_chunk 事件推送, 结束时推送 completion_done 或 completion_error,
+// 事件负载中带有请求的 requestId 以便前端区分并发请求
+func (m *ModelManager) Complete(req types.CompletionRequest) (types.CompletionResult, error) {
+	m.logger.Debug("开始补全", "requestID", req.RequestID, "model", req.Model, "raw", req.Raw, "stream", req.Stream, "hasSuffix", req.Suffix != "")
+
+	if req.Model == "" {
+		return types.CompletionResult{}, fmt.Errorf("模型名称不能为空")
+	}
+	if req.Raw && (req.Template != "" || req.System != "") {
+		return types.CompletionResult{}, fmt.Errorf("raw 模式下不能同时指定模板或系统提示词")
+	}
+	if err := ValidateConversationModelParams(req.Params); err != nil {
+		return types.CompletionResult{}, err
+	}
+	if req.RequestID == "" {
+		req.RequestID = GenerateUniqueID()
+	}
+
+	client, err := m.serverClient(req.ServerID)
+	if err != nil {
+		return types.CompletionResult{}, err
+	}
+	body := OllamaGenerateRequest{
+		Model:    req.Model,
+		Prompt:   req.Prompt,
+		Suffix:   req.Suffix,
+		System:   req.System,
+		Template: req.Template,
+		Raw:      req.Raw,
+		Context:  req.Context,
+		Options:  ToOllamaOptions(req.Params),
+		Stream:   req.Stream,
+	}
+
+	if !req.Stream {
+		return m.completeBlocking(client, req.RequestID, body)
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.Error("流式补全goroutine发生恐慌", "panic", r)
+				runtime.EventsEmit(m.ctx, "completion_error", map[string]interface{}{"requestId": req.RequestID, "error": fmt.Sprintf("内部错误: %v", r)})
+			}
+		}()
+		result, err := m.completeStream(client, req.RequestID, body)
+		if err != nil {
+			m.logger.Error("流式补全失败", "requestID", req.RequestID, "error", err)
+			runtime.EventsEmit(m.ctx, "completion_error", map[string]interface{}{"requestId": req.RequestID, "error": err.Error()})
+			return
+		}
+		runtime.EventsEmit(m.ctx, "completion_done", result)
+	}()
+	return types.CompletionResult{RequestID: req.RequestID}, nil
+}
+
+// completeBlocking 阻塞式补全
+func (m *ModelManager) completeBlocking(client *core.HttpCli, requestID string, body OllamaGenerateRequest) (types.CompletionResult, error) {
+	response, err := client.Post("/api/generate", core.Options{
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    body,
+	})
+	if err != nil {
+		m.logger.Error("补全请求失败", "error", err)
+		return types.CompletionResult{}, err
+	}
+	if err := HandleHTTPError(response.StatusCode, response.Body, m.logger, "补全"); err != nil {
+		return types.CompletionResult{}, err
+	}
+
+	var chunk ollamaGenerateResponse
+	if err := UnmarshalJSONWithError([]byte(response.Body), &chunk, m.logger, "解析补全响应"); err != nil {
+		return types.CompletionResult{}, err
+	}
+	if chunk.Error != "" {
+		return types.CompletionResult{}, fmt.Errorf("补全错误: %s", chunk.Error)
+	}
+	return completionResult(requestID, chunk.Response, chunk), nil
+}
+
+// completeStream 流式补全, 每个分块通过 completion_chunk 事件推送
+func (m *ModelManager) completeStream(client *core.HttpCli, requestID string, body OllamaGenerateRequest) (types.CompletionResult, error) {
+	resp, err := client.PostStream("/api/generate", core.Options{
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    body,
+	})
+	if err != nil {
+		return types.CompletionResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return types.CompletionResult{}, fmt.Errorf("流式补全失败，状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var text strings.Builder
+	var last ollamaGenerateResponse
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // 最后一个分块包含完整的 context, 可能很长
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaGenerateResponse
+		if err := UnmarshalJSONWithError(line, &chunk, m.logger, "解析流式补全响应"); err != nil {
+			continue
+		}
+		if chunk.Error != "" {
+			return types.CompletionResult{}, fmt.Errorf("补全错误: %s", chunk.Error)
+		}
+
+		if chunk.Response != "" {
+			text.WriteString(chunk.Response)
+			runtime.EventsEmit(m.ctx, "completion_chunk", map[string]interface{}{"requestId": requestID, "content": chunk.Response})
+		}
+		if chunk.Done {
+			last = chunk
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return types.CompletionResult{}, fmt.Errorf("读取流式响应失败: %w", err)
+	}
+
+	return completionResult(requestID, text.String(), last), nil
+}
+
+// completionResult 根据最终分块中的统计信息构造补全结果
+func completionResult(requestID string, text string, final ollamaGenerateResponse) types.CompletionResult {
+	result := types.CompletionResult{
+		RequestID:       requestID,
+		Response:        text,
+		Context:         final.Context,
+		DoneReason:      final.DoneReason,
+		PromptEvalCount: final.PromptEvalCount,
+		EvalCount:       final.EvalCount,
+		TotalDurationMs: time.Duration(final.TotalDuration).Milliseconds(),
+	}
+	if final.EvalDuration > 0 {
+		result.TokensPerSecond = float64(final.EvalCount) / time.Duration(final.EvalDuration).Seconds()
+	}
+	return result
+}
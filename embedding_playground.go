@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/16chusi/duolasdk"
+	"github.com/16chusi/duolasdk/core"
+)
+
+// embeddingSetsKey 向量集合存储的哈希键
+const embeddingSetsKey = "embedding_sets"
+
+// EmbeddingPlayground 向量模型试验场, 用于测试和对比嵌入模型
+type EmbeddingPlayground struct {
+	store    *duolasdk.AppStore
+	embedder Embedder
+	logger   *core.AppLog
+}
+
+// NewEmbeddingPlayground 创建向量试验场实例
+func NewEmbeddingPlayground(store *duolasdk.AppStore, embedder Embedder, logger *core.AppLog) *EmbeddingPlayground {
+	return &EmbeddingPlayground{
+		store:    store,
+		embedder: embedder,
+		logger:   logger.WithPrefix("EmbeddingPlayground"),
+	}
+}
+
+// EmbedTexts 为一组输入生成向量并计算两两之间的相似度
+func (p *EmbeddingPlayground) EmbedTexts(serverID string, model string, inputs []string) (types.EmbeddingResult, error) {
+	inputs = nonEmptyInputs(inputs)
+	if len(inputs) == 0 {
+		return types.EmbeddingResult{}, fmt.Errorf("至少需要一条输入文本")
+	}
+
+	start := time.Now()
+	embeddings, err := p.embedder.Embed(serverID, model, inputs)
+	if err != nil {
+		p.logger.Error("生成向量失败", "model", model, "error", err)
+		return types.EmbeddingResult{}, err
+	}
+
+	result := types.EmbeddingResult{
+		ServerID:   serverID,
+		Model:      model,
+		Inputs:     inputs,
+		Embeddings: embeddings,
+		Dimensions: len(embeddings[0]),
+		Similarity: CosineSimilarityMatrix(embeddings, embeddings),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	p.logger.Debug("生成向量完成", "model", model, "inputCount", len(inputs), "dimensions", result.Dimensions, "durationMs", result.DurationMs)
+	return result, nil
+}
+
+// SaveEmbeddingSet 为输入生成向量并保存为集合
+func (p *EmbeddingPlayground) SaveEmbeddingSet(name string, serverID string, model string, inputs []string) (*types.EmbeddingSet, error) {
+	if strings.TrimSpace(name) == "" {
+		name = fmt.Sprintf("%s (%d)", model, len(inputs))
+	}
+	result, err := p.EmbedTexts(serverID, model, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &types.EmbeddingSet{
+		ID:         GenerateUniqueID(),
+		Name:       strings.TrimSpace(name),
+		ServerID:   serverID,
+		Model:      model,
+		Inputs:     result.Inputs,
+		Embeddings: result.Embeddings,
+		Dimensions: result.Dimensions,
+		CreatedAt:  GetCurrentTimestamp(),
+	}
+	data, err := MarshalJSONWithError(set, p.logger, "序列化向量集合")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.store.HSet(embeddingSetsKey, set.ID, string(data)); err != nil {
+		p.logger.Error("保存向量集合失败", "id", set.ID, "error", err)
+		return nil, fmt.Errorf("保存向量集合失败: %w", err)
+	}
+	p.logger.Info("向量集合保存成功", "id", set.ID, "model", model)
+	return set, nil
+}
+
+// ListEmbeddingSets 获取所有向量集合, 按创建时间倒序排列, 不包含向量数据
+func (p *EmbeddingPlayground) ListEmbeddingSets() ([]types.EmbeddingSet, error) {
+	dataMap, err := p.store.HGetAll(embeddingSetsKey)
+	if err != nil {
+		p.logger.Error("获取向量集合列表失败", "error", err)
+		return nil, fmt.Errorf("获取向量集合列表失败: %w", err)
+	}
+
+	sets := make([]types.EmbeddingSet, 0, len(dataMap))
+	for _, data := range dataMap {
+		var set types.EmbeddingSet
+		if err := UnmarshalJSONWithError([]byte(data), &set, p.logger, "解析向量集合"); err != nil {
+			continue
+		}
+		set.Embeddings = nil
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].CreatedAt > sets[j].CreatedAt })
+	return sets, nil
+}
+
+// GetEmbeddingSet 获取指定ID的向量集合
+func (p *EmbeddingPlayground) GetEmbeddingSet(id string) (*types.EmbeddingSet, error) {
+	data, err := p.store.HGet(embeddingSetsKey, id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("向量集合不存在: %s", id)
+	}
+	var set types.EmbeddingSet
+	if err := UnmarshalJSONWithError([]byte(data), &set, p.logger, "解析向量集合"); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// DeleteEmbeddingSet 删除向量集合
+func (p *EmbeddingPlayground) DeleteEmbeddingSet(id string) error {
+	if err := p.store.HDel(embeddingSetsKey, id); err != nil {
+		p.logger.Error("删除向量集合失败", "id", id, "error", err)
+		return fmt.Errorf("删除向量集合失败: %w", err)
+	}
+	return nil
+}
+
+// CompareEmbeddingSets 对比输入相同的多个向量集合
+// 不同模型的向量维度不同无法直接比较, 因此比较各自的相似度矩阵
+func (p *EmbeddingPlayground) CompareEmbeddingSets(ids []string) (types.EmbeddingComparison, error) {
+	if len(ids) < 2 {
+		return types.EmbeddingComparison{}, fmt.Errorf("至少需要两个向量集合")
+	}
+
+	var comparison types.EmbeddingComparison
+	for i, id := range ids {
+		set, err := p.GetEmbeddingSet(id)
+		if err != nil {
+			return types.EmbeddingComparison{}, err
+		}
+		if i == 0 {
+			comparison.Inputs = set.Inputs
+		} else if !sameStrings(comparison.Inputs, set.Inputs) {
+			return types.EmbeddingComparison{}, fmt.Errorf("向量集合 %s 的输入与 %s 不一致, 无法对比", set.Name, comparison.Sets[0])
+		}
+
+		matrix := CosineSimilarityMatrix(set.Embeddings, set.Embeddings)
+		comparison.Sets = append(comparison.Sets, set.Name)
+		comparison.Models = append(comparison.Models, set.Model)
+		comparison.Matrices = append(comparison.Matrices, matrix)
+		comparison.Agreement = append(comparison.Agreement, matrixCorrelation(comparison.Matrices[0], matrix))
+	}
+	return comparison, nil
+}
+
+// CosineSimilarity 计算两个向量的余弦相似度, 长度不同或存在零向量时返回 0
+func CosineSimilarity(a []float64, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// CosineSimilarityMatrix 计算 rows 与 cols 中向量两两之间的余弦相似度
+func CosineSimilarityMatrix(rows [][]float64, cols [][]float64) [][]float64 {
+	matrix := make([][]float64, len(rows))
+	for i := range rows {
+		matrix[i] = make([]float64, len(cols))
+		for j := range cols {
+			matrix[i][j] = CosineSimilarity(rows[i], cols[j])
+		}
+	}
+	return matrix
+}
+
+// matrixCorrelation 计算两个相似度矩阵上三角部分 (不含对角线) 的皮尔逊相关系数
+func matrixCorrelation(a [][]float64, b [][]float64) float64 {
+	var xs, ys []float64
+	for i := range a {
+		for j := i + 1; j < len(a[i]); j++ {
+			xs = append(xs, a[i][j])
+			ys = append(ys, b[i][j])
+		}
+	}
+	if len(xs) < 2 {
+		return 1
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// nonEmptyInputs 去掉空白输入
+func nonEmptyInputs(inputs []string) []string {
+	result := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		if strings.TrimSpace(input) != "" {
+			result = append(result, input)
+		}
+	}
+	return result
+}
+
+// sameStrings 判断两个字符串切片是否完全相同
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,28 +2,94 @@
 // This file is automatically generated. DO NOT EDIT
 import {types} from '../models';
 
+export function AddImageAttachmentFromBase64(arg1:string,arg2:string):Promise<types.Attachment>;
+
 export function AddServer(arg1:types.OllamaServerConfig):Promise<void>;
 
+export function CancelApiStream(arg1:string):Promise<void>;
+
 export function ChatMessage(arg1:string,arg2:Array<types.Message>,arg3:boolean):Promise<string>;
 
+export function ClearApiHistory():Promise<void>;
+
+export function CompareEmbeddingSets(arg1:Array<string>):Promise<types.EmbeddingComparison>;
+
+export function Complete(arg1:types.CompletionRequest):Promise<types.CompletionResult>;
+
+export function DeleteApiCollection(arg1:string):Promise<void>;
+
+export function DeleteApiFolder(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteApiHistoryEntry(arg1:string):Promise<void>;
+
+export function DeleteApiRequest(arg1:string,arg2:string):Promise<void>;
+
 export function DeleteConversation(arg1:string):Promise<void>;
 
+export function DeleteEmbeddingSet(arg1:string):Promise<void>;
+
+export function DeleteKnowledgeCollection(arg1:string):Promise<void>;
+
+export function DeleteMetaPromptProfile(arg1:string):Promise<void>;
+
 export function DeleteModel(arg1:string):Promise<void>;
 
+export function DeletePersonaTemplate(arg1:string):Promise<void>;
+
 export function DeletePrompt(arg1:string):Promise<void>;
 
 export function DeleteServer(arg1:string):Promise<void>;
 
+export function DeleteTool(arg1:string):Promise<void>;
+
+export function DiffPromptRevisions(arg1:string,arg2:number,arg3:number):Promise<types.PromptRevisionDiff>;
+
 export function DownloadModel(arg1:string,arg2:string):Promise<void>;
 
-export function GeneratePromptStream(arg1:string,arg2:string,arg3:string):Promise<void>;
+export function EmbedTexts(arg1:string,arg2:string,arg3:Array<string>):Promise<types.EmbeddingResult>;
+
+export function ExportConversation(arg1:string,arg2:string):Promise<string>;
+
+export function ExportConversations(arg1:Array<string>,arg2:string):Promise<string>;
+
+export function ExportConversationsToFile(arg1:Array<string>,arg2:string):Promise<string>;
+
+export function ExportPostmanCollection(arg1:string):Promise<string>;
+
+export function ExportPostmanCollectionToFile(arg1:string):Promise<string>;
+
+export function ExportPrompts(arg1:Array<string>,arg2:string,arg3:boolean):Promise<string>;
+
+export function ExportPromptsToFile(arg1:Array<string>,arg2:string,arg3:boolean):Promise<string>;
+
+export function GenerateApiSnippets(arg1:types.ApiRequest):Promise<types.ApiCodeSnippets>;
+
+export function GeneratePromptCandidates(arg1:types.PromptGenerationRequest):Promise<string>;
+
+export function GeneratePromptStream(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function GenerateStructured(arg1:types.StructuredRequest):Promise<types.StructuredResult>;
 
 export function GetActiveServer():Promise<types.OllamaServerConfig>;
 
 export function GetAdapterAPIDocs():Promise<Record<string, string>>;
 
+export function GetApiCollection(arg1:string):Promise<types.ApiCollection>;
+
+export function GetApiHistoryEntry(arg1:string):Promise<types.ApiHistoryEntry>;
+
+export function GetAttachmentDataURL(arg1:types.Attachment):Promise<string>;
+
 export function GetConversation(arg1:string):Promise<types.Conversation>;
 
+export function GetDefaultMetaPromptProfile():Promise<types.MetaPromptProfile>;
+
+export function GetEmbeddingSet(arg1:string):Promise<types.EmbeddingSet>;
+
+export function GetKnowledgeCollection(arg1:string):Promise<types.KnowledgeCollection>;
+
+export function GetMetaPromptProfile(arg1:string):Promise<types.MetaPromptProfile>;
+
 export function GetModelParams(arg1:string):Promise<Record<string, any>>;
 
 export function GetOllamaServers():Promise<Array<types.OllamaServerConfig>>;
@@ -32,38 +98,140 @@ export function GetOpenAIAdapterConfig():Promise<types.OpenAIAdapterConfig>;
 
 export function GetOpenAIAdapterStatus():Promise<types.OpenAIAdapterStatus>;
 
+export function GetPersonaTemplate(arg1:string):Promise<types.PersonaTemplate>;
+
 export function GetPrompt(arg1:string):Promise<types.Prompt>;
 
+export function GetPromptRevision(arg1:string,arg2:number):Promise<types.PromptRevision>;
+
+export function GetPromptSuite(arg1:string):Promise<types.PromptSuite>;
+
+export function GetPromptUsage(arg1:string):Promise<types.PromptUsageStats>;
+
+export function GetPromptVariables(arg1:string):Promise<Array<types.PromptVariable>>;
+
 export function GetServers():Promise<Array<types.OllamaServerConfig>>;
 
+export function GetToolSettings():Promise<types.ToolSettings>;
+
+export function ImportConversations(arg1:string,arg2:string,arg3:boolean):Promise<types.ImportReport>;
+
+export function ImportConversationsFromFile(arg1:string,arg2:string,arg3:boolean):Promise<types.ImportReport>;
+
+export function ImportPostmanCollection(arg1:string):Promise<types.PostmanImportReport>;
+
+export function ImportPostmanCollectionFromFile(arg1:string):Promise<types.PostmanImportReport>;
+
+export function ImportPrompts(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<types.PromptImportReport>;
+
+export function ImportPromptsFromFiles(arg1:Array<string>,arg2:string,arg3:boolean):Promise<types.PromptImportReport>;
+
+export function IngestKnowledgeFiles(arg1:string,arg2:Array<string>):Promise<types.IngestReport>;
+
+export function ListApiCollections():Promise<Array<types.ApiCollection>>;
+
+export function ListApiHistory(arg1:number):Promise<Array<types.ApiHistoryEntry>>;
+
 export function ListConversations():Promise<Array<types.Conversation>>;
 
+export function ListEmbeddingSets():Promise<Array<types.EmbeddingSet>>;
+
+export function ListKnowledgeCollections():Promise<Array<types.KnowledgeCollection>>;
+
+export function ListMetaPromptProfiles():Promise<Array<types.MetaPromptProfile>>;
+
 export function ListModelsByServer(arg1:string):Promise<Array<types.Model>>;
 
+export function ListPersonaTemplates():Promise<Array<types.PersonaTemplate>>;
+
+export function ListPromptRevisions(arg1:string):Promise<Array<types.PromptRevision>>;
+
+export function ListPromptSuiteRuns(arg1:string):Promise<Array<types.PromptSuiteRun>>;
+
+export function ListPromptTags():Promise<Array<types.PromptFacet>>;
+
 export function ListPrompts():Promise<Array<types.Prompt>>;
 
+export function ListTools():Promise<Array<types.ToolDefinition>>;
+
 export function OpenInBrowser(arg1:string):Promise<void>;
 
-export function OptimizePrompt(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
+export function OptimizePrompt(arg1:string,arg2:string,arg3:string,arg4:string):Promise<types.PromptOptimizeResult>;
+
+export function ParseCurlCommand(arg1:string):Promise<types.CurlImportResult>;
+
+export function PreviewMetaPrompt(arg1:types.MetaPromptProfile):Promise<string>;
+
+export function QueryPrompts(arg1:types.PromptQuery):Promise<types.PromptQueryResult>;
+
+export function RemoveKnowledgeDocument(arg1:string,arg2:string):Promise<void>;
+
+export function RenderPrompt(arg1:string,arg2:Record<string, any>):Promise<string>;
+
+export function ReplayApiHistory(arg1:string,arg2:string):Promise<types.ApiResponse>;
+
+export function RollbackPrompt(arg1:string,arg2:number):Promise<types.Prompt>;
 
 export function RunModel(arg1:string,arg2:Record<string, any>):Promise<void>;
 
+export function RunPromptSuite(arg1:string,arg2:Array<types.ModelTarget>):Promise<types.PromptSuiteRun>;
+
+export function SaveApiCollection(arg1:types.ApiCollection):Promise<types.ApiCollection>;
+
+export function SaveApiRequest(arg1:string,arg2:types.ApiSavedRequest):Promise<types.ApiSavedRequest>;
+
 export function SaveConversation(arg1:types.Conversation):Promise<types.Conversation>;
 
+export function SaveEmbeddingSet(arg1:string,arg2:string,arg3:string,arg4:Array<string>):Promise<types.EmbeddingSet>;
+
+export function SaveKnowledgeCollection(arg1:types.KnowledgeCollection):Promise<types.KnowledgeCollection>;
+
+export function SaveMetaPromptProfile(arg1:types.MetaPromptProfile):Promise<types.MetaPromptProfile>;
+
 export function SaveOpenAIAdapterConfig(arg1:types.OpenAIAdapterConfig):Promise<void>;
 
+export function SavePersonaTemplate(arg1:types.PersonaTemplate):Promise<types.PersonaTemplate>;
+
 export function SavePrompt(arg1:types.Prompt):Promise<void>;
 
+export function SavePromptSuite(arg1:types.PromptSuite):Promise<types.PromptSuite>;
+
+export function SaveTool(arg1:types.ToolDefinition):Promise<void>;
+
+export function SaveToolSettings(arg1:types.ToolSettings):Promise<void>;
+
+export function SearchKnowledge(arg1:Array<string>,arg2:string,arg3:number):Promise<Array<types.KnowledgeHit>>;
+
 export function SearchOnlineModels(arg1:string):Promise<Array<any>>;
 
+export function SelectImageAttachments():Promise<Array<types.Attachment>>;
+
+export function SelectImportFile():Promise<string>;
+
+export function SelectKnowledgeDirectory():Promise<string>;
+
+export function SelectKnowledgeFiles():Promise<Array<string>>;
+
+export function SelectPostmanCollectionFile():Promise<string>;
+
+export function SelectPromptImportFiles():Promise<Array<string>>;
+
+export function SendChat(arg1:types.ChatRequest):Promise<types.ChatResponse>;
+
 export function SendHttpRequest(arg1:types.ApiRequest):Promise<types.ApiResponse>;
 
+export function SendHttpRequestStream(arg1:types.ApiRequest,arg2:string):Promise<string>;
+
 export function SetActiveServer(arg1:string):Promise<void>;
 
+export function SetDefaultMetaPromptProfile(arg1:string):Promise<void>;
+
 export function SetModelParams(arg1:string,arg2:Record<string, any>):Promise<void>;
 
 export function StartAdapterServer():Promise<void>;
 
+export function StartConversationFromTemplate(arg1:string):Promise<types.Conversation>;
+
 export function StopAdapterServer():Promise<void>;
 
 export function StopModel(arg1:string):Promise<void>;
@@ -2,22 +2,74 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddImageAttachmentFromBase64(arg1, arg2) {
+  return window['go']['main']['App']['AddImageAttachmentFromBase64'](arg1, arg2);
+}
+
 export function AddServer(arg1) {
   return window['go']['main']['App']['AddServer'](arg1);
 }
 
+export function CancelApiStream(arg1) {
+  return window['go']['main']['App']['CancelApiStream'](arg1);
+}
+
 export function ChatMessage(arg1, arg2, arg3) {
   return window['go']['main']['App']['ChatMessage'](arg1, arg2, arg3);
 }
 
+export function ClearApiHistory() {
+  return window['go']['main']['App']['ClearApiHistory']();
+}
+
+export function CompareEmbeddingSets(arg1) {
+  return window['go']['main']['App']['CompareEmbeddingSets'](arg1);
+}
+
+export function Complete(arg1) {
+  return window['go']['main']['App']['Complete'](arg1);
+}
+
+export function DeleteApiCollection(arg1) {
+  return window['go']['main']['App']['DeleteApiCollection'](arg1);
+}
+
+export function DeleteApiFolder(arg1, arg2) {
+  return window['go']['main']['App']['DeleteApiFolder'](arg1, arg2);
+}
+
+export function DeleteApiHistoryEntry(arg1) {
+  return window['go']['main']['App']['DeleteApiHistoryEntry'](arg1);
+}
+
+export function DeleteApiRequest(arg1, arg2) {
+  return window['go']['main']['App']['DeleteApiRequest'](arg1, arg2);
+}
+
 export function DeleteConversation(arg1) {
   return window['go']['main']['App']['DeleteConversation'](arg1);
 }
 
+export function DeleteEmbeddingSet(arg1) {
+  return window['go']['main']['App']['DeleteEmbeddingSet'](arg1);
+}
+
+export function DeleteKnowledgeCollection(arg1) {
+  return window['go']['main']['App']['DeleteKnowledgeCollection'](arg1);
+}
+
+export function DeleteMetaPromptProfile(arg1) {
+  return window['go']['main']['App']['DeleteMetaPromptProfile'](arg1);
+}
+
 export function DeleteModel(arg1) {
   return window['go']['main']['App']['DeleteModel'](arg1);
 }
 
+export function DeletePersonaTemplate(arg1) {
+  return window['go']['main']['App']['DeletePersonaTemplate'](arg1);
+}
+
 export function DeletePrompt(arg1) {
   return window['go']['main']['App']['DeletePrompt'](arg1);
 }
@@ -26,12 +78,64 @@ export function DeleteServer(arg1) {
   return window['go']['main']['App']['DeleteServer'](arg1);
 }
 
+export function DeleteTool(arg1) {
+  return window['go']['main']['App']['DeleteTool'](arg1);
+}
+
+export function DiffPromptRevisions(arg1, arg2, arg3) {
+  return window['go']['main']['App']['DiffPromptRevisions'](arg1, arg2, arg3);
+}
+
 export function DownloadModel(arg1, arg2) {
   return window['go']['main']['App']['DownloadModel'](arg1, arg2);
 }
 
-export function GeneratePromptStream(arg1, arg2, arg3) {
-  return window['go']['main']['App']['GeneratePromptStream'](arg1, arg2, arg3);
+export function EmbedTexts(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EmbedTexts'](arg1, arg2, arg3);
+}
+
+export function ExportConversation(arg1, arg2) {
+  return window['go']['main']['App']['ExportConversation'](arg1, arg2);
+}
+
+export function ExportConversations(arg1, arg2) {
+  return window['go']['main']['App']['ExportConversations'](arg1, arg2);
+}
+
+export function ExportConversationsToFile(arg1, arg2) {
+  return window['go']['main']['App']['ExportConversationsToFile'](arg1, arg2);
+}
+
+export function ExportPostmanCollection(arg1) {
+  return window['go']['main']['App']['ExportPostmanCollection'](arg1);
+}
+
+export function ExportPostmanCollectionToFile(arg1) {
+  return window['go']['main']['App']['ExportPostmanCollectionToFile'](arg1);
+}
+
+export function ExportPrompts(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportPrompts'](arg1, arg2, arg3);
+}
+
+export function ExportPromptsToFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportPromptsToFile'](arg1, arg2, arg3);
+}
+
+export function GenerateApiSnippets(arg1) {
+  return window['go']['main']['App']['GenerateApiSnippets'](arg1);
+}
+
+export function GeneratePromptCandidates(arg1) {
+  return window['go']['main']['App']['GeneratePromptCandidates'](arg1);
+}
+
+export function GeneratePromptStream(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['GeneratePromptStream'](arg1, arg2, arg3, arg4);
+}
+
+export function GenerateStructured(arg1) {
+  return window['go']['main']['App']['GenerateStructured'](arg1);
 }
 
 export function GetActiveServer() {
@@ -42,10 +146,38 @@ export function GetAdapterAPIDocs() {
   return window['go']['main']['App']['GetAdapterAPIDocs']();
 }
 
+export function GetApiCollection(arg1) {
+  return window['go']['main']['App']['GetApiCollection'](arg1);
+}
+
+export function GetApiHistoryEntry(arg1) {
+  return window['go']['main']['App']['GetApiHistoryEntry'](arg1);
+}
+
+export function GetAttachmentDataURL(arg1) {
+  return window['go']['main']['App']['GetAttachmentDataURL'](arg1);
+}
+
 export function GetConversation(arg1) {
   return window['go']['main']['App']['GetConversation'](arg1);
 }
 
+export function GetDefaultMetaPromptProfile() {
+  return window['go']['main']['App']['GetDefaultMetaPromptProfile']();
+}
+
+export function GetEmbeddingSet(arg1) {
+  return window['go']['main']['App']['GetEmbeddingSet'](arg1);
+}
+
+export function GetKnowledgeCollection(arg1) {
+  return window['go']['main']['App']['GetKnowledgeCollection'](arg1);
+}
+
+export function GetMetaPromptProfile(arg1) {
+  return window['go']['main']['App']['GetMetaPromptProfile'](arg1);
+}
+
 export function GetModelParams(arg1) {
   return window['go']['main']['App']['GetModelParams'](arg1);
 }
@@ -62,26 +194,118 @@ export function GetOpenAIAdapterStatus() {
   return window['go']['main']['App']['GetOpenAIAdapterStatus']();
 }
 
+export function GetPersonaTemplate(arg1) {
+  return window['go']['main']['App']['GetPersonaTemplate'](arg1);
+}
+
 export function GetPrompt(arg1) {
   return window['go']['main']['App']['GetPrompt'](arg1);
 }
 
+export function GetPromptRevision(arg1, arg2) {
+  return window['go']['main']['App']['GetPromptRevision'](arg1, arg2);
+}
+
+export function GetPromptSuite(arg1) {
+  return window['go']['main']['App']['GetPromptSuite'](arg1);
+}
+
+export function GetPromptUsage(arg1) {
+  return window['go']['main']['App']['GetPromptUsage'](arg1);
+}
+
+export function GetPromptVariables(arg1) {
+  return window['go']['main']['App']['GetPromptVariables'](arg1);
+}
+
 export function GetServers() {
   return window['go']['main']['App']['GetServers']();
 }
 
+export function GetToolSettings() {
+  return window['go']['main']['App']['GetToolSettings']();
+}
+
+export function ImportConversations(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportConversations'](arg1, arg2, arg3);
+}
+
+export function ImportConversationsFromFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportConversationsFromFile'](arg1, arg2, arg3);
+}
+
+export function ImportPostmanCollection(arg1) {
+  return window['go']['main']['App']['ImportPostmanCollection'](arg1);
+}
+
+export function ImportPostmanCollectionFromFile(arg1) {
+  return window['go']['main']['App']['ImportPostmanCollectionFromFile'](arg1);
+}
+
+export function ImportPrompts(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportPrompts'](arg1, arg2, arg3, arg4);
+}
+
+export function ImportPromptsFromFiles(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ImportPromptsFromFiles'](arg1, arg2, arg3);
+}
+
+export function IngestKnowledgeFiles(arg1, arg2) {
+  return window['go']['main']['App']['IngestKnowledgeFiles'](arg1, arg2);
+}
+
+export function ListApiCollections() {
+  return window['go']['main']['App']['ListApiCollections']();
+}
+
+export function ListApiHistory(arg1) {
+  return window['go']['main']['App']['ListApiHistory'](arg1);
+}
+
 export function ListConversations() {
   return window['go']['main']['App']['ListConversations']();
 }
 
+export function ListEmbeddingSets() {
+  return window['go']['main']['App']['ListEmbeddingSets']();
+}
+
+export function ListKnowledgeCollections() {
+  return window['go']['main']['App']['ListKnowledgeCollections']();
+}
+
+export function ListMetaPromptProfiles() {
+  return window['go']['main']['App']['ListMetaPromptProfiles']();
+}
+
 export function ListModelsByServer(arg1) {
   return window['go']['main']['App']['ListModelsByServer'](arg1);
 }
 
+export function ListPersonaTemplates() {
+  return window['go']['main']['App']['ListPersonaTemplates']();
+}
+
+export function ListPromptRevisions(arg1) {
+  return window['go']['main']['App']['ListPromptRevisions'](arg1);
+}
+
+export function ListPromptSuiteRuns(arg1) {
+  return window['go']['main']['App']['ListPromptSuiteRuns'](arg1);
+}
+
+export function ListPromptTags() {
+  return window['go']['main']['App']['ListPromptTags']();
+}
+
 export function ListPrompts() {
   return window['go']['main']['App']['ListPrompts']();
 }
 
+export function ListTools() {
+  return window['go']['main']['App']['ListTools']();
+}
+
 export function OpenInBrowser(arg1) {
   return window['go']['main']['App']['OpenInBrowser'](arg1);
 }
@@ -90,34 +314,142 @@ export function OptimizePrompt(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['OptimizePrompt'](arg1, arg2, arg3, arg4);
 }
 
+export function ParseCurlCommand(arg1) {
+  return window['go']['main']['App']['ParseCurlCommand'](arg1);
+}
+
+export function PreviewMetaPrompt(arg1) {
+  return window['go']['main']['App']['PreviewMetaPrompt'](arg1);
+}
+
+export function QueryPrompts(arg1) {
+  return window['go']['main']['App']['QueryPrompts'](arg1);
+}
+
+export function RemoveKnowledgeDocument(arg1, arg2) {
+  return window['go']['main']['App']['RemoveKnowledgeDocument'](arg1, arg2);
+}
+
+export function RenderPrompt(arg1, arg2) {
+  return window['go']['main']['App']['RenderPrompt'](arg1, arg2);
+}
+
+export function ReplayApiHistory(arg1, arg2) {
+  return window['go']['main']['App']['ReplayApiHistory'](arg1, arg2);
+}
+
+export function RollbackPrompt(arg1, arg2) {
+  return window['go']['main']['App']['RollbackPrompt'](arg1, arg2);
+}
+
 export function RunModel(arg1, arg2) {
   return window['go']['main']['App']['RunModel'](arg1, arg2);
 }
 
+export function RunPromptSuite(arg1, arg2) {
+  return window['go']['main']['App']['RunPromptSuite'](arg1, arg2);
+}
+
+export function SaveApiCollection(arg1) {
+  return window['go']['main']['App']['SaveApiCollection'](arg1);
+}
+
+export function SaveApiRequest(arg1, arg2) {
+  return window['go']['main']['App']['SaveApiRequest'](arg1, arg2);
+}
+
 export function SaveConversation(arg1) {
   return window['go']['main']['App']['SaveConversation'](arg1);
 }
 
+export function SaveEmbeddingSet(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SaveEmbeddingSet'](arg1, arg2, arg3, arg4);
+}
+
+export function SaveKnowledgeCollection(arg1) {
+  return window['go']['main']['App']['SaveKnowledgeCollection'](arg1);
+}
+
+export function SaveMetaPromptProfile(arg1) {
+  return window['go']['main']['App']['SaveMetaPromptProfile'](arg1);
+}
+
 export function SaveOpenAIAdapterConfig(arg1) {
   return window['go']['main']['App']['SaveOpenAIAdapterConfig'](arg1);
 }
 
+export function SavePersonaTemplate(arg1) {
+  return window['go']['main']['App']['SavePersonaTemplate'](arg1);
+}
+
 export function SavePrompt(arg1) {
   return window['go']['main']['App']['SavePrompt'](arg1);
 }
 
+export function SavePromptSuite(arg1) {
+  return window['go']['main']['App']['SavePromptSuite'](arg1);
+}
+
+export function SaveTool(arg1) {
+  return window['go']['main']['App']['SaveTool'](arg1);
+}
+
+export function SaveToolSettings(arg1) {
+  return window['go']['main']['App']['SaveToolSettings'](arg1);
+}
+
+export function SearchKnowledge(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchKnowledge'](arg1, arg2, arg3);
+}
+
 export function SearchOnlineModels(arg1) {
   return window['go']['main']['App']['SearchOnlineModels'](arg1);
 }
 
+export function SelectImageAttachments() {
+  return window['go']['main']['App']['SelectImageAttachments']();
+}
+
+export function SelectImportFile() {
+  return window['go']['main']['App']['SelectImportFile']();
+}
+
+export function SelectKnowledgeDirectory() {
+  return window['go']['main']['App']['SelectKnowledgeDirectory']();
+}
+
+export function SelectKnowledgeFiles() {
+  return window['go']['main']['App']['SelectKnowledgeFiles']();
+}
+
+export function SelectPostmanCollectionFile() {
+  return window['go']['main']['App']['SelectPostmanCollectionFile']();
+}
+
+export function SelectPromptImportFiles() {
+  return window['go']['main']['App']['SelectPromptImportFiles']();
+}
+
+export function SendChat(arg1) {
+  return window['go']['main']['App']['SendChat'](arg1);
+}
+
 export function SendHttpRequest(arg1) {
   return window['go']['main']['App']['SendHttpRequest'](arg1);
 }
 
+export function SendHttpRequestStream(arg1, arg2) {
+  return window['go']['main']['App']['SendHttpRequestStream'](arg1, arg2);
+}
+
 export function SetActiveServer(arg1) {
   return window['go']['main']['App']['SetActiveServer'](arg1);
 }
 
+export function SetDefaultMetaPromptProfile(arg1) {
+  return window['go']['main']['App']['SetDefaultMetaPromptProfile'](arg1);
+}
+
 export function SetModelParams(arg1, arg2) {
   return window['go']['main']['App']['SetModelParams'](arg1, arg2);
 }
@@ -126,6 +458,10 @@ export function StartAdapterServer() {
   return window['go']['main']['App']['StartAdapterServer']();
 }
 
+export function StartConversationFromTemplate(arg1) {
+  return window['go']['main']['App']['StartConversationFromTemplate'](arg1);
+}
+
 export function StopAdapterServer() {
   return window['go']['main']['App']['StopAdapterServer']();
 }
@@ -89,6 +89,7 @@ export namespace types {
 	    queryParams: QueryParam[];
 	    headers: RequestHeader[];
 	    body: RequestBody;
+	    collectionId?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new ApiRequest(source);
@@ -102,6 +103,7 @@ export namespace types {
 	        this.queryParams = this.convertValues(source["queryParams"], QueryParam);
 	        this.headers = this.convertValues(source["headers"], RequestHeader);
 	        this.body = this.convertValues(source["body"], RequestBody);
+	        this.collectionId = source["collectionId"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -165,7 +167,11 @@ export namespace types {
 	export class Message {
 	    role: string;
 	    content: string;
+	    thinking?: string;
 	    timestamp: number;
+	    attachments?: Attachment[];
+	    toolCalls?: ToolCall[];
+	    toolName?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Message(source);
@@ -175,16 +181,41 @@ export namespace types {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.role = source["role"];
 	        this.content = source["content"];
+	        this.thinking = source["thinking"];
 	        this.timestamp = source["timestamp"];
+	        this.attachments = this.convertValues(source["attachments"], Attachment);
+	        this.toolCalls = this.convertValues(source["toolCalls"], ToolCall);
+	        this.toolName = source["toolName"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 	export class Conversation {
 	    id: string;
 	    title: string;
 	    messages: Message[];
 	    modelName: string;
+	    serverId?: string;
 	    systemPrompt: string;
 	    modelParams: string;
+	    tools?: string[];
+	    collections?: string[];
 	    timestamp: number;
 	
 	    static createFrom(source: any = {}) {
@@ -197,8 +228,11 @@ export namespace types {
 	        this.title = source["title"];
 	        this.messages = this.convertValues(source["messages"], Message);
 	        this.modelName = source["modelName"];
+	        this.serverId = source["serverId"];
 	        this.systemPrompt = source["systemPrompt"];
 	        this.modelParams = source["modelParams"];
+	        this.tools = source["tools"];
+	        this.collections = source["collections"];
 	        this.timestamp = source["timestamp"];
 	    }
 	
@@ -309,6 +343,7 @@ export namespace types {
 	    version: number;
 	    tags: string[];
 	    createdBy: string;
+	    variables?: PromptVariable[];
 	
 	    static createFrom(source: any = {}) {
 	        return new Prompt(source);
@@ -326,10 +361,1657 @@ export namespace types {
 	        this.version = source["version"];
 	        this.tags = source["tags"];
 	        this.createdBy = source["createdBy"];
+	        this.variables = this.convertValues(source["variables"], PromptVariable);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ImportItem {
+	    title: string;
+	    messageCount: number;
+	    contentHash: string;
+	    status: string;
+	    conversationId?: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.messageCount = source["messageCount"];
+	        this.contentHash = source["contentHash"];
+	        this.status = source["status"];
+	        this.conversationId = source["conversationId"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class ImportReport {
+	    source: string;
+	    dryRun: boolean;
+	    total: number;
+	    imported: number;
+	    duplicates: number;
+	    invalid: number;
+	    items: ImportItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.dryRun = source["dryRun"];
+	        this.total = source["total"];
+	        this.imported = source["imported"];
+	        this.duplicates = source["duplicates"];
+	        this.invalid = source["invalid"];
+	        this.items = this.convertValues(source["items"], ImportItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Attachment {
+	    id: string;
+	    name: string;
+	    mimeType: string;
+	    size: number;
+	    width?: number;
+	    height?: number;
+	    thumbnail?: string;
+	    path?: string;
+	    data?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Attachment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.mimeType = source["mimeType"];
+	        this.size = source["size"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.thumbnail = source["thumbnail"];
+	        this.path = source["path"];
+	        this.data = source["data"];
+	    }
+	}
+	export class ToolCallFunction {
+	    name: string;
+	    arguments: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCallFunction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.arguments = source["arguments"];
+	    }
+	}
+	export class ToolCall {
+	    function: ToolCallFunction;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.function = this.convertValues(source["function"], ToolCallFunction);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatRequest {
+	    conversationId?: string;
+	    modelName: string;
+	    messages: Message[];
+	    stream: boolean;
+	    tools?: string[];
+	    think?: boolean;
+	    collections?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.conversationId = source["conversationId"];
+	        this.modelName = source["modelName"];
+	        this.messages = this.convertValues(source["messages"], Message);
+	        this.stream = source["stream"];
+	        this.tools = source["tools"];
+	        this.think = source["think"];
+	        this.collections = source["collections"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ChatResponse {
+	    content: string;
+	    thinking?: string;
+	    messages?: Message[];
+	    citations?: KnowledgeHit[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ChatResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.thinking = source["thinking"];
+	        this.messages = this.convertValues(source["messages"], Message);
+	        this.citations = this.convertValues(source["citations"], KnowledgeHit);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolDefinition {
+	    name: string;
+	    description: string;
+	    parameters: Record<string, any>;
+	    builtin: boolean;
+	    sideEffect: boolean;
+	    endpoint?: string;
+	    enabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolDefinition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parameters = source["parameters"];
+	        this.builtin = source["builtin"];
+	        this.sideEffect = source["sideEffect"];
+	        this.endpoint = source["endpoint"];
+	        this.enabled = source["enabled"];
+	    }
+	}
+	export class ToolSettings {
+	    sandboxDir: string;
+	    httpAllowList: string[];
+	    maxToolRounds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sandboxDir = source["sandboxDir"];
+	        this.httpAllowList = source["httpAllowList"];
+	        this.maxToolRounds = source["maxToolRounds"];
+	    }
+	}
+	export class StructuredRequest {
+	    modelName: string;
+	    mode: string;
+	    messages?: Message[];
+	    prompt?: string;
+	    system?: string;
+	    schema: string;
+	    maxRetries: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StructuredRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.modelName = source["modelName"];
+	        this.mode = source["mode"];
+	        this.messages = this.convertValues(source["messages"], Message);
+	        this.prompt = source["prompt"];
+	        this.system = source["system"];
+	        this.schema = source["schema"];
+	        this.maxRetries = source["maxRetries"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StructuredResult {
+	    data: any;
+	    raw: string;
+	    valid: boolean;
+	    attempts: number;
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StructuredResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.data = source["data"];
+	        this.raw = source["raw"];
+	        this.valid = source["valid"];
+	        this.attempts = source["attempts"];
+	        this.errors = source["errors"];
 	    }
 	}
+	export class KnowledgeHit {
+	    collectionId: string;
+	    collectionName: string;
+	    documentId: string;
+	    documentName: string;
+	    chunkIndex: number;
+	    content: string;
+	    score: number;
 	
+	    static createFrom(source: any = {}) {
+	        return new KnowledgeHit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.collectionId = source["collectionId"];
+	        this.collectionName = source["collectionName"];
+	        this.documentId = source["documentId"];
+	        this.documentName = source["documentName"];
+	        this.chunkIndex = source["chunkIndex"];
+	        this.content = source["content"];
+	        this.score = source["score"];
+	    }
+	}
+	export class KnowledgeDocument {
+	    id: string;
+	    name: string;
+	    path: string;
+	    size: number;
+	    chunkCount: number;
+	    ingestedAt: number;
 	
+	    static createFrom(source: any = {}) {
+	        return new KnowledgeDocument(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.chunkCount = source["chunkCount"];
+	        this.ingestedAt = source["ingestedAt"];
+	    }
+	}
+	export class KnowledgeCollection {
+	    id: string;
+	    name: string;
+	    description: string;
+	    serverId: string;
+	    embeddingModel: string;
+	    chunkSize: number;
+	    chunkOverlap: number;
+	    documents: KnowledgeDocument[];
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new KnowledgeCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.serverId = source["serverId"];
+	        this.embeddingModel = source["embeddingModel"];
+	        this.chunkSize = source["chunkSize"];
+	        this.chunkOverlap = source["chunkOverlap"];
+	        this.documents = this.convertValues(source["documents"], KnowledgeDocument);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IngestReport {
+	    collectionId: string;
+	    documents: KnowledgeDocument[];
+	    errors?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IngestReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.collectionId = source["collectionId"];
+	        this.documents = this.convertValues(source["documents"], KnowledgeDocument);
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EmbeddingResult {
+	    serverId: string;
+	    model: string;
+	    inputs: string[];
+	    embeddings: number[][];
+	    dimensions: number;
+	    similarity: number[][];
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbeddingResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.serverId = source["serverId"];
+	        this.model = source["model"];
+	        this.inputs = source["inputs"];
+	        this.embeddings = source["embeddings"];
+	        this.dimensions = source["dimensions"];
+	        this.similarity = source["similarity"];
+	        this.durationMs = source["durationMs"];
+	    }
+	}
+	export class EmbeddingSet {
+	    id: string;
+	    name: string;
+	    serverId: string;
+	    model: string;
+	    inputs: string[];
+	    embeddings?: number[][];
+	    dimensions: number;
+	    createdAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbeddingSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.serverId = source["serverId"];
+	        this.model = source["model"];
+	        this.inputs = source["inputs"];
+	        this.embeddings = source["embeddings"];
+	        this.dimensions = source["dimensions"];
+	        this.createdAt = source["createdAt"];
+	    }
+	}
+	export class EmbeddingComparison {
+	    inputs: string[];
+	    sets: string[];
+	    models: string[];
+	    matrices: number[][][];
+	    agreement: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbeddingComparison(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inputs = source["inputs"];
+	        this.sets = source["sets"];
+	        this.models = source["models"];
+	        this.matrices = source["matrices"];
+	        this.agreement = source["agreement"];
+	    }
+	}
+	export class ConversationModelParams {
+	    temperature?: number;
+	    topP?: number;
+	    topK?: number;
+	    context?: number;
+	    numPredict?: number;
+	    repeatPenalty?: number;
+	    seed?: number;
+	    stop?: string[];
+	    outputMode?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConversationModelParams(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.temperature = source["temperature"];
+	        this.topP = source["topP"];
+	        this.topK = source["topK"];
+	        this.context = source["context"];
+	        this.numPredict = source["numPredict"];
+	        this.repeatPenalty = source["repeatPenalty"];
+	        this.seed = source["seed"];
+	        this.stop = source["stop"];
+	        this.outputMode = source["outputMode"];
+	    }
+	}
+	export class CompletionRequest {
+	    requestId: string;
+	    serverId: string;
+	    model: string;
+	    prompt: string;
+	    suffix?: string;
+	    system?: string;
+	    template?: string;
+	    raw: boolean;
+	    context?: number[];
+	    params: ConversationModelParams;
+	    stream: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompletionRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestId = source["requestId"];
+	        this.serverId = source["serverId"];
+	        this.model = source["model"];
+	        this.prompt = source["prompt"];
+	        this.suffix = source["suffix"];
+	        this.system = source["system"];
+	        this.template = source["template"];
+	        this.raw = source["raw"];
+	        this.context = source["context"];
+	        this.params = this.convertValues(source["params"], ConversationModelParams);
+	        this.stream = source["stream"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CompletionResult {
+	    requestId: string;
+	    response: string;
+	    context?: number[];
+	    doneReason?: string;
+	    promptEvalCount: number;
+	    evalCount: number;
+	    totalDurationMs: number;
+	    tokensPerSecond: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CompletionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestId = source["requestId"];
+	        this.response = source["response"];
+	        this.context = source["context"];
+	        this.doneReason = source["doneReason"];
+	        this.promptEvalCount = source["promptEvalCount"];
+	        this.evalCount = source["evalCount"];
+	        this.totalDurationMs = source["totalDurationMs"];
+	        this.tokensPerSecond = source["tokensPerSecond"];
+	    }
+	}
+	export class PersonaTemplate {
+	    id: string;
+	    name: string;
+	    description: string;
+	    serverId: string;
+	    modelName: string;
+	    systemPrompt: string;
+	    params: ConversationModelParams;
+	    seedMessages: Message[];
+	    tools: string[];
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PersonaTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.serverId = source["serverId"];
+	        this.modelName = source["modelName"];
+	        this.systemPrompt = source["systemPrompt"];
+	        this.params = this.convertValues(source["params"], ConversationModelParams);
+	        this.seedMessages = this.convertValues(source["seedMessages"], Message);
+	        this.tools = source["tools"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptVariable {
+	    name: string;
+	    label?: string;
+	    type: string;
+	    description?: string;
+	    default?: string;
+	    options?: string[];
+	    required: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.description = source["description"];
+	        this.default = source["default"];
+	        this.options = source["options"];
+	        this.required = source["required"];
+	    }
+	}
+	export class DiffLine {
+	    type: string;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.text = source["text"];
+	    }
+	}
+	export class TextDiff {
+	    lines: DiffLine[];
+	    added: number;
+	    removed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TextDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lines = this.convertValues(source["lines"], DiffLine);
+	        this.added = source["added"];
+	        this.removed = source["removed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptOptimizeResult {
+	    original: string;
+	    optimized: string;
+	    diff: TextDiff;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptOptimizeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.original = source["original"];
+	        this.optimized = source["optimized"];
+	        this.diff = this.convertValues(source["diff"], TextDiff);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptRevision {
+	    promptId: string;
+	    version: number;
+	    prompt: Prompt;
+	    author: string;
+	    note?: string;
+	    timestamp: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptRevision(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.promptId = source["promptId"];
+	        this.version = source["version"];
+	        this.prompt = this.convertValues(source["prompt"], Prompt);
+	        this.author = source["author"];
+	        this.note = source["note"];
+	        this.timestamp = source["timestamp"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptRevisionDiff {
+	    fromVersion: number;
+	    toVersion: number;
+	    content: TextDiff;
+	    changes: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptRevisionDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fromVersion = source["fromVersion"];
+	        this.toVersion = source["toVersion"];
+	        this.content = this.convertValues(source["content"], TextDiff);
+	        this.changes = source["changes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptExpectation {
+	    type: string;
+	    value: string;
+	    weight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptExpectation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.value = source["value"];
+	        this.weight = source["weight"];
+	    }
+	}
+	export class PromptTestCase {
+	    id: string;
+	    name: string;
+	    variables: Record<string, any>;
+	    input?: string;
+	    expectations: PromptExpectation[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptTestCase(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.variables = source["variables"];
+	        this.input = source["input"];
+	        this.expectations = this.convertValues(source["expectations"], PromptExpectation);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelTarget {
+	    serverId: string;
+	    model: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelTarget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.serverId = source["serverId"];
+	        this.model = source["model"];
+	    }
+	}
+	export class PromptSuite {
+	    promptId: string;
+	    cases: PromptTestCase[];
+	    judge: ModelTarget;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptSuite(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.promptId = source["promptId"];
+	        this.cases = this.convertValues(source["cases"], PromptTestCase);
+	        this.judge = this.convertValues(source["judge"], ModelTarget);
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptCheckResult {
+	    type: string;
+	    passed: boolean;
+	    score: number;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptCheckResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.passed = source["passed"];
+	        this.score = source["score"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class PromptCaseResult {
+	    caseId: string;
+	    caseName: string;
+	    target: ModelTarget;
+	    output: string;
+	    checks: PromptCheckResult[];
+	    score: number;
+	    passed: boolean;
+	    error?: string;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptCaseResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.caseId = source["caseId"];
+	        this.caseName = source["caseName"];
+	        this.target = this.convertValues(source["target"], ModelTarget);
+	        this.output = source["output"];
+	        this.checks = this.convertValues(source["checks"], PromptCheckResult);
+	        this.score = source["score"];
+	        this.passed = source["passed"];
+	        this.error = source["error"];
+	        this.durationMs = source["durationMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptTargetScore {
+	    target: ModelTarget;
+	    score: number;
+	    passed: number;
+	    total: number;
+	    delta?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptTargetScore(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = this.convertValues(source["target"], ModelTarget);
+	        this.score = source["score"];
+	        this.passed = source["passed"];
+	        this.total = source["total"];
+	        this.delta = source["delta"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptSuiteRun {
+	    id: string;
+	    promptId: string;
+	    promptVersion: number;
+	    results: PromptCaseResult[];
+	    scores: PromptTargetScore[];
+	    score: number;
+	    baselineRunId?: string;
+	    baselineVersion?: number;
+	    scoreDelta?: number;
+	    startedAt: number;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptSuiteRun(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.promptId = source["promptId"];
+	        this.promptVersion = source["promptVersion"];
+	        this.results = this.convertValues(source["results"], PromptCaseResult);
+	        this.scores = this.convertValues(source["scores"], PromptTargetScore);
+	        this.score = source["score"];
+	        this.baselineRunId = source["baselineRunId"];
+	        this.baselineVersion = source["baselineVersion"];
+	        this.scoreDelta = source["scoreDelta"];
+	        this.startedAt = source["startedAt"];
+	        this.durationMs = source["durationMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptGenerationRequest {
+	    requestId: string;
+	    idea: string;
+	    targets: ModelTarget[];
+	    judge?: ModelTarget;
+	    profileId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptGenerationRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requestId = source["requestId"];
+	        this.idea = source["idea"];
+	        this.targets = this.convertValues(source["targets"], ModelTarget);
+	        this.judge = this.convertValues(source["judge"], ModelTarget);
+	        this.profileId = source["profileId"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MetaPromptProfile {
+	    id: string;
+	    name: string;
+	    language: string;
+	    sections: string[];
+	    modelFamily: string;
+	    instructions: string;
+	    builtIn: boolean;
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MetaPromptProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.language = source["language"];
+	        this.sections = source["sections"];
+	        this.modelFamily = source["modelFamily"];
+	        this.instructions = source["instructions"];
+	        this.builtIn = source["builtIn"];
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	}
+	export class PromptImportItem {
+	    name: string;
+	    status: string;
+	    promptId?: string;
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptImportItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.promptId = source["promptId"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class PromptImportReport {
+	    dryRun: boolean;
+	    total: number;
+	    imported: number;
+	    overwritten: number;
+	    skipped: number;
+	    invalid: number;
+	    items: PromptImportItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dryRun = source["dryRun"];
+	        this.total = source["total"];
+	        this.imported = source["imported"];
+	        this.overwritten = source["overwritten"];
+	        this.skipped = source["skipped"];
+	        this.invalid = source["invalid"];
+	        this.items = this.convertValues(source["items"], PromptImportItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptQuery {
+	    text: string;
+	    tags: string[];
+	    models: string[];
+	    createdBy: string;
+	    sortBy: string;
+	    sortOrder: string;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.tags = source["tags"];
+	        this.models = source["models"];
+	        this.createdBy = source["createdBy"];
+	        this.sortBy = source["sortBy"];
+	        this.sortOrder = source["sortOrder"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class PromptFacet {
+	    value: string;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptFacet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.count = source["count"];
+	    }
+	}
+	export class PromptQueryResult {
+	    prompts: Prompt[];
+	    total: number;
+	    tags: PromptFacet[];
+	    models: PromptFacet[];
+	    creators: PromptFacet[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptQueryResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.prompts = this.convertValues(source["prompts"], Prompt);
+	        this.total = source["total"];
+	        this.tags = this.convertValues(source["tags"], PromptFacet);
+	        this.models = this.convertValues(source["models"], PromptFacet);
+	        this.creators = this.convertValues(source["creators"], PromptFacet);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PromptVersionUsage {
+	    version: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptVersionUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.count = source["count"];
+	    }
+	}
+	export class PromptUsage {
+	    promptId: string;
+	    version: number;
+	    source: string;
+	    conversationId?: string;
+	    conversationTitle?: string;
+	    serverId?: string;
+	    model: string;
+	    count: number;
+	    firstUsedAt: number;
+	    lastUsedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.promptId = source["promptId"];
+	        this.version = source["version"];
+	        this.source = source["source"];
+	        this.conversationId = source["conversationId"];
+	        this.conversationTitle = source["conversationTitle"];
+	        this.serverId = source["serverId"];
+	        this.model = source["model"];
+	        this.count = source["count"];
+	        this.firstUsedAt = source["firstUsedAt"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	    }
+	}
+	export class PromptUsageStats {
+	    promptId: string;
+	    totalCount: number;
+	    conversationCount: number;
+	    lastUsedAt: number;
+	    versions: PromptVersionUsage[];
+	    models: PromptFacet[];
+	    recent: PromptUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PromptUsageStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.promptId = source["promptId"];
+	        this.totalCount = source["totalCount"];
+	        this.conversationCount = source["conversationCount"];
+	        this.lastUsedAt = source["lastUsedAt"];
+	        this.versions = this.convertValues(source["versions"], PromptVersionUsage);
+	        this.models = this.convertValues(source["models"], PromptFacet);
+	        this.recent = this.convertValues(source["recent"], PromptUsage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApiSavedRequest {
+	    id: string;
+	    name: string;
+	    description: string;
+	    folder: string;
+	    request: ApiRequest;
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApiSavedRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.folder = source["folder"];
+	        this.request = this.convertValues(source["request"], ApiRequest);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApiCollection {
+	    id: string;
+	    name: string;
+	    description: string;
+	    folders: string[];
+	    requests: ApiSavedRequest[];
+	    variables: ApiVariable[];
+	    createdAt: number;
+	    updatedAt: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApiCollection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.folders = source["folders"];
+	        this.requests = this.convertValues(source["requests"], ApiSavedRequest);
+	        this.variables = this.convertValues(source["variables"], ApiVariable);
+	        this.createdAt = source["createdAt"];
+	        this.updatedAt = source["updatedAt"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApiHistoryEntry {
+	    id: string;
+	    request: ApiRequest;
+	    response: ApiResponse;
+	    serverName: string;
+	    url: string;
+	    truncated: boolean;
+	    replayOf?: string;
+	    timestamp: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApiHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.request = this.convertValues(source["request"], ApiRequest);
+	        this.response = this.convertValues(source["response"], ApiResponse);
+	        this.serverName = source["serverName"];
+	        this.url = source["url"];
+	        this.truncated = source["truncated"];
+	        this.replayOf = source["replayOf"];
+	        this.timestamp = source["timestamp"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApiVariable {
+	    key: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApiVariable(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.value = source["value"];
+	    }
+	}
+	export class PostmanImportReport {
+	    collection: ApiCollection;
+	    imported: number;
+	    unmatchedBaseUrls: string[];
+	    unassignedRequests: string[];
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PostmanImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.collection = this.convertValues(source["collection"], ApiCollection);
+	        this.imported = source["imported"];
+	        this.unmatchedBaseUrls = source["unmatchedBaseUrls"];
+	        this.unassignedRequests = source["unassignedRequests"];
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ApiCodeSnippets {
+	    url: string;
+	    curl: string;
+	    httpie: string;
+	    go: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ApiCodeSnippets(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.curl = source["curl"];
+	        this.httpie = source["httpie"];
+	        this.go = source["go"];
+	    }
+	}
+	export class CurlImportResult {
+	    request: ApiRequest;
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new CurlImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.request = this.convertValues(source["request"], ApiRequest);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
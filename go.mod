@@ -6,6 +6,7 @@ require (
 	github.com/16chusi/duolasdk v1.0.8
 	github.com/google/uuid v1.6.0
 	github.com/wailsapp/wails/v2 v2.10.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace github.com/16chusi/duolasdk v1.0.8 => ../duolasdk
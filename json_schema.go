@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseJSONSchema 解析 JSON Schema 文本, 顶层必须是对象
+func ParseJSONSchema(text string) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &schema); err != nil {
+		return nil, fmt.Errorf("JSON Schema 格式错误: %w", err)
+	}
+	return schema, nil
+}
+
+// ValidateJSONSchema 按 JSON Schema 校验已解析的 JSON 值
+// 支持常用关键字: type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, uniqueItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, allOf, anyOf, oneOf, not
+func ValidateJSONSchema(schema map[string]interface{}, value interface{}) error {
+	var errs []string
+	validateSchemaNode(schema, value, "$", &errs)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// validateSchemaNode 递归校验单个节点, 错误信息带上 JSON 路径
+func validateSchemaNode(schema map[string]interface{}, value interface{}, path string, errs *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesSchemaType(t, value) {
+		fail("类型应为 %v, 实际为 %s", t, jsonTypeName(value))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("值必须是 %v 之一", enum)
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		fail("值必须等于 %v", c)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(schema, v, path, errs)
+	case []interface{}:
+		validateSchemaArray(schema, v, path, errs)
+	case string:
+		length := utf8.RuneCountInString(v)
+		if n, ok := schemaNumber(schema, "minLength"); ok && float64(length) < n {
+			fail("长度不能小于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > n {
+			fail("长度不能大于 %v", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("schema 中的正则无效: %v", err)
+			} else if !re.MatchString(v) {
+				fail("不匹配正则 %s", pattern)
+			}
+		}
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			fail("不能小于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			fail("不能大于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "exclusiveMinimum"); ok && v <= n {
+			fail("必须大于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "exclusiveMaximum"); ok && v >= n {
+			fail("必须小于 %v", n)
+		}
+		if n, ok := schemaNumber(schema, "multipleOf"); ok && n != 0 {
+			if q := v / n; math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("必须是 %v 的倍数", n)
+			}
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if subSchema, ok := sub.(map[string]interface{}); ok {
+				validateSchemaNode(subSchema, value, path, errs)
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && countSchemaMatches(anyOf, value) == 0 {
+		fail("不满足 anyOf 中的任何一个 schema")
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if n := countSchemaMatches(oneOf, value); n != 1 {
+			fail("必须恰好满足 oneOf 中的一个 schema, 实际满足 %d 个", n)
+		}
+	}
+	if not, ok := schema["not"].(map[string]interface{}); ok && countSchemaMatches([]interface{}{not}, value) == 1 {
+		fail("不能满足 not 中的 schema")
+	}
+}
+
+// validateSchemaObject 校验对象的 properties / required / additionalProperties
+func validateSchemaObject(schema map[string]interface{}, obj map[string]interface{}, path string, errs *[]string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, exists := obj[key]; !exists {
+				*errs = append(*errs, fmt.Sprintf("%s: 缺少必填字段 %q", path, key))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			validateSchemaNode(propSchema, obj[key], childPath, errs)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, fmt.Sprintf("%s: 不允许的字段", childPath))
+			}
+		case map[string]interface{}:
+			validateSchemaNode(additional, obj[key], childPath, errs)
+		}
+	}
+}
+
+// validateSchemaArray 校验数组的 items / minItems / maxItems / uniqueItems
+func validateSchemaArray(schema map[string]interface{}, arr []interface{}, path string, errs *[]string) {
+	if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(arr)) < n {
+		*errs = append(*errs, fmt.Sprintf("%s: 元素个数不能少于 %v", path, n))
+	}
+	if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(arr)) > n {
+		*errs = append(*errs, fmt.Sprintf("%s: 元素个数不能多于 %v", path, n))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					*errs = append(*errs, fmt.Sprintf("%s: 第 %d 和第 %d 个元素重复", path, i, j))
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// countSchemaMatches 统计值满足的子 schema 数量
+func countSchemaMatches(schemas []interface{}, value interface{}) int {
+	n := 0
+	for _, sub := range schemas {
+		subSchema, ok := sub.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var subErrs []string
+		validateSchemaNode(subSchema, value, "$", &subErrs)
+		if len(subErrs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// matchesSchemaType 判断值是否符合 type 关键字 (字符串或字符串数组)
+func matchesSchemaType(t interface{}, value interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesSingleType(tt, value)
+	case []interface{}:
+		for _, candidate := range tt {
+			if name, ok := candidate.(string); ok && matchesSingleType(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(t string, value interface{}) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == t
+	}
+}
+
+// jsonTypeName 返回 encoding/json 解码结果对应的 JSON 类型名
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaNumber 读取 schema 中的数值关键字
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
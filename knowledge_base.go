@@ -0,0 +1,535 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+	"unicode/utf8"
+
+	"github.com/16chusi/duolasdk"
+	"github.com/16chusi/duolasdk/core"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// knowledgeCollectionsKey 知识库集合存储的哈希键
+	knowledgeCollectionsKey = "knowledge:collections"
+	// knowledgeChunksKeyPrefix 片段存储的哈希键前缀, 每个集合一个哈希, 字段为文档ID
+	knowledgeChunksKeyPrefix = "knowledge:chunks:"
+
+	defaultChunkSize      = 1000
+	defaultChunkOverlap   = 150
+	defaultRetrievalTopK  = 4
+	maxKnowledgeFileSize  = 20 * 1024 * 1024
+	minRetrievalRelevance = 0.2 // 低于该相似度的片段视为与问题无关
+)
+
+// knowledgeTextExtensions 按纯文本读取的文件扩展名, 扫描目录时只导入这些文件和 PDF
+var knowledgeTextExtensions = map[string]bool{
+	".md": true, ".markdown": true, ".txt": true, ".rst": true, ".org": true, ".adoc": true,
+	".csv": true, ".json": true, ".yaml": true, ".yml": true, ".toml": true, ".xml": true, ".html": true, ".htm": true,
+	".ini": true, ".cfg": true, ".conf": true, ".log": true, ".sql": true, ".sh": true, ".bat": true, ".ps1": true,
+	".go": true, ".py": true, ".js": true, ".ts": true, ".jsx": true, ".tsx": true, ".vue": true, ".java": true,
+	".kt": true, ".c": true, ".h": true, ".cpp": true, ".hpp": true, ".cs": true, ".rs": true, ".rb": true,
+	".php": true, ".swift": true, ".lua": true, ".scala": true, ".dart": true, ".css": true, ".scss": true,
+}
+
+// Embedder 文本向量化能力, 由 ModelManager 实现
+type Embedder interface {
+	Embed(serverID string, model string, inputs []string) ([][]float64, error)
+}
+
+// KnowledgeBase 本地知识库, 负责文档导入、切片、向量化和检索
+type KnowledgeBase struct {
+	ctx      context.Context
+	store    *duolasdk.AppStore
+	embedder Embedder
+	logger   *core.AppLog
+}
+
+// NewKnowledgeBase 创建知识库实例
+func NewKnowledgeBase(store *duolasdk.AppStore, logger *core.AppLog) *KnowledgeBase {
+	return &KnowledgeBase{
+		store:  store,
+		logger: logger.WithPrefix("KnowledgeBase"),
+	}
+}
+
+// SetContext 设置上下文
+func (kb *KnowledgeBase) SetContext(ctx context.Context) {
+	kb.ctx = ctx
+}
+
+// SetEmbedder 设置向量化提供者
+func (kb *KnowledgeBase) SetEmbedder(embedder Embedder) {
+	kb.embedder = embedder
+}
+
+// ListCollections 获取所有知识库集合, 按创建时间倒序排列
+func (kb *KnowledgeBase) ListCollections() ([]types.KnowledgeCollection, error) {
+	dataMap, err := kb.store.HGetAll(knowledgeCollectionsKey)
+	if err != nil {
+		kb.logger.Error("获取知识库列表失败", "error", err)
+		return nil, fmt.Errorf("获取知识库列表失败: %w", err)
+	}
+
+	collections := make([]types.KnowledgeCollection, 0, len(dataMap))
+	for _, data := range dataMap {
+		var collection types.KnowledgeCollection
+		if err := UnmarshalJSONWithError([]byte(data), &collection, kb.logger, "解析知识库集合"); err != nil {
+			continue
+		}
+		collections = append(collections, collection)
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i].CreatedAt > collections[j].CreatedAt })
+	return collections, nil
+}
+
+// GetCollection 获取指定ID的知识库集合
+func (kb *KnowledgeBase) GetCollection(id string) (*types.KnowledgeCollection, error) {
+	data, err := kb.store.HGet(knowledgeCollectionsKey, id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("知识库集合不存在: %s", id)
+	}
+	var collection types.KnowledgeCollection
+	if err := UnmarshalJSONWithError([]byte(data), &collection, kb.logger, "解析知识库集合"); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// SaveCollection 创建或更新知识库集合的基本信息, 文档列表只能通过导入和删除文档修改
+func (kb *KnowledgeBase) SaveCollection(collection types.KnowledgeCollection) (*types.KnowledgeCollection, error) {
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" {
+		return nil, fmt.Errorf("知识库名称不能为空")
+	}
+	if strings.TrimSpace(collection.EmbeddingModel) == "" {
+		return nil, fmt.Errorf("嵌入模型不能为空")
+	}
+	if collection.ChunkSize <= 0 {
+		collection.ChunkSize = defaultChunkSize
+	}
+	if collection.ChunkOverlap < 0 || collection.ChunkOverlap >= collection.ChunkSize {
+		return nil, fmt.Errorf("片段重叠长度必须在 0 到片段长度之间")
+	}
+
+	now := GetCurrentTimestamp()
+	if collection.ID == "" {
+		collection.ID = GenerateUniqueID()
+		collection.CreatedAt = now
+		collection.Documents = []types.KnowledgeDocument{}
+	} else {
+		existing, err := kb.GetCollection(collection.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing.Documents) > 0 && (existing.EmbeddingModel != collection.EmbeddingModel || existing.ServerID != collection.ServerID) {
+			return nil, fmt.Errorf("已有文档的知识库不能更换嵌入模型或服务器, 请新建知识库")
+		}
+		collection.CreatedAt = existing.CreatedAt
+		collection.Documents = existing.Documents
+	}
+	collection.UpdatedAt = now
+
+	if err := kb.putCollection(&collection); err != nil {
+		return nil, err
+	}
+	kb.logger.Info("知识库保存成功", "id", collection.ID, "name", collection.Name)
+	return &collection, nil
+}
+
+// DeleteCollection 删除知识库集合及其所有片段
+func (kb *KnowledgeBase) DeleteCollection(id string) error {
+	kb.logger.Info("删除知识库", "id", id)
+	collection, err := kb.GetCollection(id)
+	if err != nil {
+		return err
+	}
+	for _, doc := range collection.Documents {
+		if err := kb.store.HDel(knowledgeChunksKeyPrefix+id, doc.ID); err != nil {
+			kb.logger.Warn("删除文档片段失败", "collectionID", id, "documentID", doc.ID, "error", err)
+		}
+	}
+	if err := kb.store.HDel(knowledgeCollectionsKey, id); err != nil {
+		kb.logger.Error("删除知识库失败", "id", id, "error", err)
+		return fmt.Errorf("删除知识库失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveDocument 从知识库中删除一个文档及其片段
+func (kb *KnowledgeBase) RemoveDocument(collectionID string, documentID string) error {
+	collection, err := kb.GetCollection(collectionID)
+	if err != nil {
+		return err
+	}
+	if err := kb.store.HDel(knowledgeChunksKeyPrefix+collectionID, documentID); err != nil {
+		kb.logger.Error("删除文档片段失败", "documentID", documentID, "error", err)
+		return fmt.Errorf("删除文档失败: %w", err)
+	}
+
+	docs := collection.Documents[:0]
+	for _, doc := range collection.Documents {
+		if doc.ID != documentID {
+			docs = append(docs, doc)
+		}
+	}
+	collection.Documents = docs
+	collection.UpdatedAt = GetCurrentTimestamp()
+	return kb.putCollection(collection)
+}
+
+// SelectKnowledgeFiles 弹出文件选择对话框, 返回选中的文档路径
+func (kb *KnowledgeBase) SelectKnowledgeFiles() ([]string, error) {
+	paths, err := runtime.OpenMultipleFilesDialog(kb.ctx, runtime.OpenDialogOptions{
+		Title: "选择要导入知识库的文档",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Documents", Pattern: "*.md;*.markdown;*.txt;*.pdf;*.rst;*.html;*.json;*.yaml;*.yml;*.csv"},
+			{DisplayName: "All Files", Pattern: "*.*"},
+		},
+	})
+	if err != nil {
+		kb.logger.Error("打开文件选择对话框失败", "error", err)
+		return nil, fmt.Errorf("打开文件选择对话框失败: %w", err)
+	}
+	return paths, nil
+}
+
+// SelectKnowledgeDirectory 弹出目录选择对话框, 用于批量导入代码仓库或文档目录
+func (kb *KnowledgeBase) SelectKnowledgeDirectory() (string, error) {
+	dir, err := runtime.OpenDirectoryDialog(kb.ctx, runtime.OpenDialogOptions{
+		Title: "选择要导入知识库的目录",
+	})
+	if err != nil {
+		kb.logger.Error("打开目录选择对话框失败", "error", err)
+		return "", fmt.Errorf("打开目录选择对话框失败: %w", err)
+	}
+	return dir, nil
+}
+
+// IngestFiles 将文件或目录导入知识库, 目录会递归扫描支持的文件类型
+// 同一路径的文档重复导入时替换旧的片段; 单个文件失败不影响其他文件, 错误记录在报告中
+// 每处理完一个文件通过 knowledge_ingest_progress 事件推送进度
+func (kb *KnowledgeBase) IngestFiles(collectionID string, paths []string) (types.IngestReport, error) {
+	report := types.IngestReport{CollectionID: collectionID, Documents: []types.KnowledgeDocument{}}
+	if kb.embedder == nil {
+		return report, fmt.Errorf("向量化提供者未设置")
+	}
+	collection, err := kb.GetCollection(collectionID)
+	if err != nil {
+		return report, err
+	}
+
+	files := expandPaths(paths, func(ext string) bool { return knowledgeTextExtensions[ext] || ext == ".pdf" })
+	kb.logger.Info("开始导入知识库文档", "collectionID", collectionID, "fileCount", len(files))
+
+	for i, path := range files {
+		doc, err := kb.ingestFile(collection, path)
+		if err != nil {
+			kb.logger.Warn("导入文档失败", "path", path, "error", err)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", path, err))
+		} else {
+			report.Documents = append(report.Documents, doc)
+		}
+		runtime.EventsEmit(kb.ctx, "knowledge_ingest_progress", map[string]interface{}{
+			"collectionId": collectionID,
+			"file":         path,
+			"current":      i + 1,
+			"total":        len(files),
+			"error":        errorString(err),
+		})
+	}
+
+	collection.UpdatedAt = GetCurrentTimestamp()
+	if err := kb.putCollection(collection); err != nil {
+		return report, err
+	}
+	kb.logger.Info("知识库文档导入完成", "collectionID", collectionID, "imported", len(report.Documents), "failed", len(report.Errors))
+	return report, nil
+}
+
+// ingestFile 读取、切片、向量化单个文件并写入存储, 同时更新集合的文档列表
+func (kb *KnowledgeBase) ingestFile(collection *types.KnowledgeCollection, path string) (types.KnowledgeDocument, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return types.KnowledgeDocument{}, fmt.Errorf("读取文件信息失败: %w", err)
+	}
+	if info.Size() > maxKnowledgeFileSize {
+		return types.KnowledgeDocument{}, fmt.Errorf("文件超过 %dMB 限制", maxKnowledgeFileSize/1024/1024)
+	}
+
+	text, err := readKnowledgeFile(path)
+	if err != nil {
+		return types.KnowledgeDocument{}, err
+	}
+	contents := ChunkText(text, collection.ChunkSize, collection.ChunkOverlap)
+	if len(contents) == 0 {
+		return types.KnowledgeDocument{}, fmt.Errorf("文件没有可导入的文本内容")
+	}
+
+	embeddings, err := kb.embedder.Embed(collection.ServerID, collection.EmbeddingModel, contents)
+	if err != nil {
+		return types.KnowledgeDocument{}, fmt.Errorf("生成向量失败: %w", err)
+	}
+
+	doc := types.KnowledgeDocument{
+		ID:         GenerateUniqueID(),
+		Name:       filepath.Base(path),
+		Path:       path,
+		Size:       info.Size(),
+		ChunkCount: len(contents),
+		IngestedAt: GetCurrentTimestamp(),
+	}
+	chunks := make([]types.KnowledgeChunk, len(contents))
+	for i, content := range contents {
+		chunks[i] = types.KnowledgeChunk{
+			ID:           fmt.Sprintf("%s-%d", doc.ID, i),
+			DocumentID:   doc.ID,
+			DocumentName: doc.Name,
+			Index:        i,
+			Content:      content,
+			Embedding:    embeddings[i],
+		}
+	}
+
+	chunksJSON, err := MarshalJSONWithError(chunks, kb.logger, "序列化文档片段")
+	if err != nil {
+		return types.KnowledgeDocument{}, err
+	}
+	if err := kb.store.HSet(knowledgeChunksKeyPrefix+collection.ID, doc.ID, string(chunksJSON)); err != nil {
+		return types.KnowledgeDocument{}, fmt.Errorf("保存文档片段失败: %w", err)
+	}
+
+	// 替换同一路径的旧文档
+	docs := make([]types.KnowledgeDocument, 0, len(collection.Documents)+1)
+	for _, existing := range collection.Documents {
+		if existing.Path == path {
+			kb.store.HDel(knowledgeChunksKeyPrefix+collection.ID, existing.ID)
+			continue
+		}
+		docs = append(docs, existing)
+	}
+	collection.Documents = append(docs, doc)
+	return doc, nil
+}
+
+// Search 在指定的知识库集合中检索与问题最相关的片段
+func (kb *KnowledgeBase) Search(collectionIDs []string, query string, topK int) ([]types.KnowledgeHit, error) {
+	if kb.embedder == nil {
+		return nil, fmt.Errorf("向量化提供者未设置")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	if topK <= 0 {
+		topK = defaultRetrievalTopK
+	}
+
+	var hits []types.KnowledgeHit
+	for _, id := range collectionIDs {
+		collection, err := kb.GetCollection(id)
+		if err != nil {
+			// 集合可能已被删除, 跳过而不是让整个检索失败
+			kb.logger.Warn("跳过无法读取的知识库集合", "collectionID", id, "error", err)
+			continue
+		}
+		if len(collection.Documents) == 0 {
+			continue
+		}
+
+		// 每个集合可能使用不同的嵌入模型, 需要分别向量化问题
+		queryEmbeddings, err := kb.embedder.Embed(collection.ServerID, collection.EmbeddingModel, []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("问题向量化失败: %w", err)
+		}
+		chunks, err := kb.loadChunks(collection.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, chunk := range chunks {
+			if len(chunk.Embedding) != len(queryEmbeddings[0]) {
+				continue
+			}
+			score := CosineSimilarity(queryEmbeddings[0], chunk.Embedding)
+			if score < minRetrievalRelevance {
+				continue
+			}
+			hits = append(hits, types.KnowledgeHit{
+				CollectionID:   collection.ID,
+				CollectionName: collection.Name,
+				DocumentID:     chunk.DocumentID,
+				DocumentName:   chunk.DocumentName,
+				ChunkIndex:     chunk.Index,
+				Content:        chunk.Content,
+				Score:          score,
+			})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if len(hits) > topK {
+		hits = hits[:topK]
+	}
+	kb.logger.Debug("知识库检索完成", "collections", collectionIDs, "hits", len(hits))
+	return hits, nil
+}
+
+// loadChunks 读取集合中所有文档的片段
+func (kb *KnowledgeBase) loadChunks(collectionID string) ([]types.KnowledgeChunk, error) {
+	dataMap, err := kb.store.HGetAll(knowledgeChunksKeyPrefix + collectionID)
+	if err != nil {
+		kb.logger.Error("读取文档片段失败", "collectionID", collectionID, "error", err)
+		return nil, fmt.Errorf("读取文档片段失败: %w", err)
+	}
+	var chunks []types.KnowledgeChunk
+	for _, data := range dataMap {
+		var docChunks []types.KnowledgeChunk
+		if err := UnmarshalJSONWithError([]byte(data), &docChunks, kb.logger, "解析文档片段"); err != nil {
+			continue
+		}
+		chunks = append(chunks, docChunks...)
+	}
+	return chunks, nil
+}
+
+// putCollection 将集合写入存储
+func (kb *KnowledgeBase) putCollection(collection *types.KnowledgeCollection) error {
+	data, err := MarshalJSONWithError(collection, kb.logger, "序列化知识库集合")
+	if err != nil {
+		return err
+	}
+	if err := kb.store.HSet(knowledgeCollectionsKey, collection.ID, string(data)); err != nil {
+		kb.logger.Error("保存知识库失败", "id", collection.ID, "error", err)
+		return fmt.Errorf("保存知识库失败: %w", err)
+	}
+	return nil
+}
+
+// BuildKnowledgeContext 将检索结果整理为注入给模型的参考资料, 序号与引用 [n] 对应
+func BuildKnowledgeContext(hits []types.KnowledgeHit) string {
+	var sb strings.Builder
+	sb.WriteString("以下是从本地知识库中检索到的参考资料。请优先依据这些资料回答用户的问题, ")
+	sb.WriteString("引用资料时在相应句子末尾用 [n] 标注来源序号; 如果资料与问题无关或不足以回答, 请直接说明, 不要编造。\n")
+	for i, hit := range hits {
+		fmt.Fprintf(&sb, "\n[%d] 来源: %s (片段 %d)\n%s\n", i+1, hit.DocumentName, hit.ChunkIndex+1, hit.Content)
+	}
+	return sb.String()
+}
+
+// ChunkText 按段落将文本切分为不超过 size 个字符的片段, 相邻片段保留 overlap 个字符的重叠
+func ChunkText(text string, size int, overlap int) []string {
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var pieces [][]rune
+	for _, paragraph := range paragraphSeparator.Split(strings.ReplaceAll(text, "\r\n", "\n"), -1) {
+		runes := []rune(strings.TrimSpace(paragraph))
+		// 超长段落按固定长度切开
+		for len(runes) > size {
+			pieces = append(pieces, runes[:size])
+			runes = runes[size-overlap:]
+		}
+		if len(runes) > 0 {
+			pieces = append(pieces, runes)
+		}
+	}
+
+	var chunks []string
+	var current []rune
+	for _, piece := range pieces {
+		if len(current) > 0 && len(current)+len(piece)+2 > size {
+			chunks = append(chunks, string(current))
+			current = tailRunes(current, overlap)
+			if len(current)+len(piece)+2 > size {
+				current = nil
+			}
+		}
+		if len(current) > 0 {
+			current = append(current, '\n', '\n')
+		}
+		current = append(current, piece...)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, string(current))
+	}
+	return chunks
+}
+
+var paragraphSeparator = regexp.MustCompile(`\n[ \t]*\n`)
+
+// tailRunes 返回末尾 n 个字符的副本
+func tailRunes(runes []rune, n int) []rune {
+	if n <= 0 {
+		return nil
+	}
+	if len(runes) > n {
+		runes = runes[len(runes)-n:]
+	}
+	return append([]rune(nil), runes...)
+}
+
+// readKnowledgeFile 读取文档的文本内容, PDF 提取文本层, 其他文件必须是 UTF-8 文本
+func readKnowledgeFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		return ExtractPDFText(data)
+	}
+	if !utf8.Valid(data) || strings.ContainsRune(string(data), 0) {
+		return "", fmt.Errorf("不支持的文件类型, 只能导入 UTF-8 文本、代码和 PDF")
+	}
+	return string(data), nil
+}
+
+// expandPaths 展开目录, 只保留 accept 接受的扩展名 (小写, 含点), 跳过隐藏目录和常见的依赖目录
+// 直接指定的文件总是保留
+func expandPaths(paths []string, accept func(ext string) bool) []string {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if p != path && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			ext := strings.ToLower(filepath.Ext(p))
+			if accept(ext) {
+				files = append(files, p)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// errorString 返回错误信息, 错误为空时返回空字符串
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+)
+
+const (
+	// metaPromptProfilesKey 元提示词配置存储的哈希键, 内置配置被修改后也保存在这里
+	metaPromptProfilesKey = "meta_prompt_profiles"
+	// defaultMetaPromptProfileKey 默认元提示词配置ID的存储键
+	defaultMetaPromptProfileKey = "meta_prompt_default"
+	// fallbackMetaPromptProfileID 未设置默认配置时使用的配置, 与早期硬编码的行为一致
+	fallbackMetaPromptProfileID = "default-zh"
+)
+
+// metaPromptSectionOrder 支持的提示词段落, 同时决定段落在元提示词中的顺序
+var metaPromptSectionOrder = []string{"role", "context", "task", "constraints", "format", "examples"}
+
+// metaPromptSectionNames 各语言下的段落标题
+var metaPromptSectionNames = map[string]map[string]string{
+	"zh": {"role": "角色", "context": "背景", "task": "任务", "constraints": "约束", "format": "输出格式", "examples": "示例"},
+	"en": {"role": "Role", "context": "Context", "task": "Task", "constraints": "Constraints", "format": "Output Format", "examples": "Examples"},
+}
+
+// metaPromptFamilyHints 针对不同模型系列的写作建议, 未列出的系列只使用通用要求
+var metaPromptFamilyHints = map[string]map[string]string{
+	"llama": {
+		"zh": "生成的提示词将用于 Llama 系列模型: 指令要直接明确, 把最重要的要求放在开头, 避免含糊的措辞。",
+		"en": "The prompt will be used with Llama models: keep instructions direct and explicit, put the most important requirement first and avoid vague wording.",
+	},
+	"qwen": {
+		"zh": "生成的提示词将用于通义千问 (Qwen) 系列模型: 可以使用 Markdown 标题和列表组织结构, 对输出格式给出明确的示例。",
+		"en": "The prompt will be used with Qwen models: organise it with Markdown headings and lists and give a concrete example of the expected output format.",
+	},
+	"mistral": {
+		"zh": "生成的提示词将用于 Mistral 系列模型: 保持简洁, 每条约束单独成行, 不要依赖隐含的上下文。",
+		"en": "The prompt will be used with Mistral models: keep it concise, put each constraint on its own line and do not rely on implicit context.",
+	},
+	"gemma": {
+		"zh": "生成的提示词将用于 Gemma 系列模型: 该系列没有独立的系统角色, 提示词需要能直接作为用户消息使用。",
+		"en": "The prompt will be used with Gemma models, which have no separate system role: the prompt must work when sent as a user message.",
+	},
+	"deepseek": {
+		"zh": "生成的提示词将用于 DeepSeek 系列模型: 推理模型会自行思考, 不要要求逐步输出推理过程, 只需说明目标和最终输出的要求。",
+		"en": "The prompt will be used with DeepSeek models: reasoning models think on their own, so do not ask for step-by-step reasoning; state the goal and the requirements for the final answer.",
+	},
+	"phi": {
+		"zh": "生成的提示词将用于 Phi 等小参数模型: 控制篇幅, 使用简单的句式, 限制一次只完成一个任务。",
+		"en": "The prompt will be used with small models such as Phi: keep it short, use simple sentences and ask for a single task at a time.",
+	},
+}
+
+// builtinMetaPromptProfiles 返回内置的元提示词配置
+func builtinMetaPromptProfiles() []types.MetaPromptProfile {
+	return []types.MetaPromptProfile{
+		{
+			ID:       "default-zh",
+			Name:     "默认 (中文)",
+			Language: "zh",
+			Sections: []string{"role", "task", "constraints", "format"},
+			BuiltIn:  true,
+		},
+		{
+			ID:       "default-en",
+			Name:     "Default (English)",
+			Language: "en",
+			Sections: []string{"role", "task", "constraints", "format"},
+			BuiltIn:  true,
+		},
+	}
+}
+
+// builtinMetaPromptProfile 根据ID查找内置配置
+func builtinMetaPromptProfile(id string) (types.MetaPromptProfile, bool) {
+	for _, profile := range builtinMetaPromptProfiles() {
+		if profile.ID == id {
+			return profile, true
+		}
+	}
+	return types.MetaPromptProfile{}, false
+}
+
+// BuildMetaPrompt 根据配置生成发给模型的系统提示词
+func BuildMetaPrompt(profile types.MetaPromptProfile) string {
+	lang := metaPromptLanguage(profile.Language)
+	names := metaPromptSectionNames[lang]
+
+	var sb strings.Builder
+	if lang == "en" {
+		sb.WriteString("You are an expert prompt engineer. Your task is to turn the idea provided by the user into a clear, specific and effective prompt for a large language model.")
+	} else {
+		sb.WriteString(generatorMetaPrompt)
+	}
+
+	if len(profile.Sections) > 0 {
+		titles := make([]string, 0, len(profile.Sections))
+		for _, section := range profile.Sections {
+			titles = append(titles, names[section])
+		}
+		if lang == "en" {
+			fmt.Fprintf(&sb, "\nOrganise the prompt into the following sections, each under a Markdown level-two heading, in this order: %s.", strings.Join(titles, ", "))
+		} else {
+			fmt.Fprintf(&sb, "\n提示词按以下段落组织, 每段使用 Markdown 二级标题, 顺序为: %s。", strings.Join(titles, "、"))
+		}
+	}
+
+	if hint := metaPromptFamilyHints[profile.ModelFamily][lang]; hint != "" {
+		sb.WriteString("\n" + hint)
+	}
+	if instructions := strings.TrimSpace(profile.Instructions); instructions != "" {
+		sb.WriteString("\n" + instructions)
+	}
+
+	if lang == "en" {
+		sb.WriteString("\nWrite the prompt in English and output only the prompt itself, without any explanation or preamble.")
+	} else {
+		sb.WriteString("\n提示词使用中文撰写, 只输出提示词本身, 不要添加任何解释或前言。")
+	}
+	return sb.String()
+}
+
+// metaPromptUserMessage 生成包含用户想法的消息, 与配置的语言保持一致
+func metaPromptUserMessage(profile types.MetaPromptProfile, idea string) string {
+	if metaPromptLanguage(profile.Language) == "en" {
+		return fmt.Sprintf("Here is my idea: %s", idea)
+	}
+	return fmt.Sprintf("这是我的想法：%s", idea)
+}
+
+func metaPromptLanguage(language string) string {
+	if language == "en" {
+		return "en"
+	}
+	return "zh"
+}
+
+// ListMetaPromptProfiles 获取所有元提示词配置, 内置配置在前, 自定义配置按名称排序
+func (p *PromptEngineering) ListMetaPromptProfiles() ([]types.MetaPromptProfile, error) {
+	dataMap, err := p.store.HGetAll(metaPromptProfilesKey)
+	if err != nil {
+		p.logger.Error("获取元提示词配置失败", "error", err)
+		return nil, fmt.Errorf("获取元提示词配置失败: %w", err)
+	}
+
+	stored := make(map[string]types.MetaPromptProfile, len(dataMap))
+	for id, data := range dataMap {
+		var profile types.MetaPromptProfile
+		if err := UnmarshalJSONWithError([]byte(data), &profile, p.logger, "解析元提示词配置"); err != nil {
+			continue
+		}
+		stored[id] = profile
+	}
+
+	var profiles, custom []types.MetaPromptProfile
+	for _, builtin := range builtinMetaPromptProfiles() {
+		if profile, ok := stored[builtin.ID]; ok {
+			builtin = profile
+			delete(stored, builtin.ID)
+		}
+		profiles = append(profiles, builtin)
+	}
+	for _, profile := range stored {
+		custom = append(custom, profile)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(profiles, custom...), nil
+}
+
+// GetMetaPromptProfile 获取指定ID的元提示词配置
+func (p *PromptEngineering) GetMetaPromptProfile(id string) (types.MetaPromptProfile, error) {
+	data, err := p.store.HGet(metaPromptProfilesKey, id)
+	if err == nil && data != "" {
+		var profile types.MetaPromptProfile
+		if err := UnmarshalJSONWithError([]byte(data), &profile, p.logger, "解析元提示词配置"); err != nil {
+			return types.MetaPromptProfile{}, err
+		}
+		return profile, nil
+	}
+	if profile, ok := builtinMetaPromptProfile(id); ok {
+		return profile, nil
+	}
+	return types.MetaPromptProfile{}, fmt.Errorf("元提示词配置不存在: %s", id)
+}
+
+// SaveMetaPromptProfile 创建或更新元提示词配置, 修改内置配置时保存为覆盖版本
+func (p *PromptEngineering) SaveMetaPromptProfile(profile types.MetaPromptProfile) (types.MetaPromptProfile, error) {
+	if err := validateMetaPromptProfile(&profile); err != nil {
+		p.logger.Warn("元提示词配置校验失败", "name", profile.Name, "error", err)
+		return types.MetaPromptProfile{}, err
+	}
+
+	now := GetCurrentTimestamp()
+	_, profile.BuiltIn = builtinMetaPromptProfile(profile.ID)
+	if profile.ID == "" {
+		profile.ID = GenerateUniqueID()
+		profile.CreatedAt = now
+	} else if existing, err := p.GetMetaPromptProfile(profile.ID); err == nil {
+		profile.CreatedAt = existing.CreatedAt
+	}
+	profile.UpdatedAt = now
+
+	data, err := MarshalJSONWithError(profile, p.logger, "序列化元提示词配置")
+	if err != nil {
+		return types.MetaPromptProfile{}, err
+	}
+	if err := p.store.HSet(metaPromptProfilesKey, profile.ID, string(data)); err != nil {
+		p.logger.Error("保存元提示词配置失败", "id", profile.ID, "error", err)
+		return types.MetaPromptProfile{}, fmt.Errorf("保存元提示词配置失败: %w", err)
+	}
+	p.logger.Info("元提示词配置保存成功", "id", profile.ID, "name", profile.Name)
+	return profile, nil
+}
+
+// DeleteMetaPromptProfile 删除自定义配置; 对内置配置则恢复为默认内容
+func (p *PromptEngineering) DeleteMetaPromptProfile(id string) error {
+	p.logger.Info("删除元提示词配置", "id", id)
+	if err := p.store.HDel(metaPromptProfilesKey, id); err != nil {
+		p.logger.Error("删除元提示词配置失败", "id", id, "error", err)
+		return fmt.Errorf("删除元提示词配置失败: %w", err)
+	}
+	if _, builtin := builtinMetaPromptProfile(id); !builtin && p.defaultMetaPromptProfileID() == id {
+		if err := p.store.Delete(defaultMetaPromptProfileKey); err != nil {
+			p.logger.Warn("重置默认元提示词配置失败", "error", err)
+		}
+	}
+	return nil
+}
+
+// GetDefaultMetaPromptProfile 获取未指定配置时使用的元提示词配置
+func (p *PromptEngineering) GetDefaultMetaPromptProfile() (types.MetaPromptProfile, error) {
+	return p.GetMetaPromptProfile(p.defaultMetaPromptProfileID())
+}
+
+// SetDefaultMetaPromptProfile 设置默认的元提示词配置
+func (p *PromptEngineering) SetDefaultMetaPromptProfile(id string) error {
+	if _, err := p.GetMetaPromptProfile(id); err != nil {
+		return err
+	}
+	if err := p.store.Set(defaultMetaPromptProfileKey, id); err != nil {
+		p.logger.Error("设置默认元提示词配置失败", "id", id, "error", err)
+		return fmt.Errorf("设置默认元提示词配置失败: %w", err)
+	}
+	p.logger.Info("默认元提示词配置已更新", "id", id)
+	return nil
+}
+
+// PreviewMetaPrompt 返回配置对应的系统提示词, 供编辑配置时预览
+func (p *PromptEngineering) PreviewMetaPrompt(profile types.MetaPromptProfile) (string, error) {
+	if err := validateMetaPromptProfile(&profile); err != nil {
+		return "", err
+	}
+	return BuildMetaPrompt(profile), nil
+}
+
+// resolveMetaPromptProfile 按ID取得配置, ID为空时使用默认配置
+func (p *PromptEngineering) resolveMetaPromptProfile(id string) (types.MetaPromptProfile, error) {
+	if id != "" {
+		return p.GetMetaPromptProfile(id)
+	}
+	profile, err := p.GetDefaultMetaPromptProfile()
+	if err != nil {
+		// 默认配置已失效时退回内置配置
+		p.logger.Warn("默认元提示词配置不可用, 使用内置配置", "error", err)
+		return p.GetMetaPromptProfile(fallbackMetaPromptProfileID)
+	}
+	return profile, nil
+}
+
+func (p *PromptEngineering) defaultMetaPromptProfileID() string {
+	id, err := p.store.Get(defaultMetaPromptProfileKey)
+	if err != nil || id == "" {
+		return fallbackMetaPromptProfileID
+	}
+	return id
+}
+
+// validateMetaPromptProfile 校验并规范化元提示词配置
+func validateMetaPromptProfile(profile *types.MetaPromptProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return fmt.Errorf("配置名称不能为空")
+	}
+	switch profile.Language {
+	case "zh", "en":
+	case "":
+		profile.Language = "zh"
+	default:
+		return fmt.Errorf("不支持的语言: %s", profile.Language)
+	}
+	profile.ModelFamily = strings.ToLower(strings.TrimSpace(profile.ModelFamily))
+
+	selected := make(map[string]bool, len(profile.Sections))
+	for _, section := range profile.Sections {
+		if _, ok := metaPromptSectionNames["zh"][section]; !ok {
+			return fmt.Errorf("不支持的段落: %s", section)
+		}
+		selected[section] = true
+	}
+	// 去重并按固定顺序排列段落
+	profile.Sections = profile.Sections[:0]
+	for _, section := range metaPromptSectionOrder {
+		if selected[section] {
+			profile.Sections = append(profile.Sections, section)
+		}
+	}
+	return nil
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"tools-ollama/types"
 
 	"github.com/16chusi/duolasdk/core"
@@ -18,8 +21,86 @@ type ModelManager struct {
 	app       *App // 保留对App的引用以访问全局状态和方法
 	logger    *core.AppLog
 	configMgr *OllamaConfigManager
+
+	thinkPrefill sync.Map // 服务器地址|模型 -> 模型模板是否预填了 <think>
+}
+
+// OllamaChatMessage /api/chat 请求中的消息结构
+// core.Message 只有 role 和 content, 这里额外携带多模态模型需要的图片和工具调用信息
+type OllamaChatMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Images    []string         `json:"images,omitempty"` // base64 编码的图片
+	ToolCalls []types.ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// OllamaTool /api/chat 请求中的工具声明
+type OllamaTool struct {
+	Type     string             `json:"type"`
+	Function OllamaToolFunction `json:"function"`
+}
+
+// OllamaToolFunction 工具声明中的函数描述
+type OllamaToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// OllamaChatRequest /api/chat 请求体
+type OllamaChatRequest struct {
+	ServerID string                 `json:"-"` // 目标服务器, 为空时使用活动服务器
+	Model    string                 `json:"model"`
+	Messages []OllamaChatMessage    `json:"messages"`
+	Tools    []OllamaTool           `json:"tools,omitempty"`
+	Format   interface{}            `json:"format,omitempty"`  // "json" 或 JSON Schema 对象
+	Think    *bool                  `json:"think,omitempty"`   // 是否让推理模型单独返回思考过程
+	Options  map[string]interface{} `json:"options,omitempty"` // 模型参数, 如 temperature / num_ctx
+	Stream   bool                   `json:"stream"`
+}
+
+// OllamaGenerateRequest /api/generate 请求体
+type OllamaGenerateRequest struct {
+	Model    string                 `json:"model"`
+	Prompt   string                 `json:"prompt"`
+	Suffix   string                 `json:"suffix,omitempty"` // 光标之后的内容, 用于代码补全 (FIM)
+	System   string                 `json:"system,omitempty"`
+	Template string                 `json:"template,omitempty"` // 覆盖模型自带的提示词模板
+	Raw      bool                   `json:"raw,omitempty"`      // 为 true 时不套用任何模板
+	Context  []int                  `json:"context,omitempty"`  // 上一次补全返回的上下文, 用于延续对话
+	Format   interface{}            `json:"format,omitempty"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+	Stream   bool                   `json:"stream"`
+}
+
+// ChatResult 一次 /api/chat 调用的结果
+type ChatResult struct {
+	Content   string
+	Thinking  string
+	ToolCalls []types.ToolCall
+}
+
+// ChatChunk 流式聊天中的一个增量片段, Thinking 和 Content 通常只有一个非空
+type ChatChunk struct {
+	Thinking string
+	Content  string
+}
+
+// ollamaChatResponse /api/chat 的响应 (流式时为其中一行)
+type ollamaChatResponse struct {
+	Message struct {
+		Content   string           `json:"content"`
+		Thinking  string           `json:"thinking"`
+		ToolCalls []types.ToolCall `json:"tool_calls"`
+	} `json:"message"`
+	Done  bool   `json:"done"`
+	Error string `json:"error"`
 }
 
+// embedBatchSize 单次 /api/embed 请求的最大输入条数
+const embedBatchSize = 32
+
 // 模型运行状态管理 (在内存中)
 var runningModels = make(map[string]*types.RunningModel)
 
@@ -310,68 +391,228 @@ func (m *ModelManager) GetModelParams(modelName string) (types.ModelParams, erro
 }
 
 // Chat 实现AIProvider接口的阻塞式聊天方法
-func (m *ModelManager) Chat(model string, messages []core.Message) (string, error) {
-	m.logger.Debug("开始阻塞式聊天", "model", model, "messageCount", len(messages))
-
-	if m.app.httpClient == nil {
-		return "", fmt.Errorf("HTTP客户端未初始化")
-	}
+func (m *ModelManager) Chat(req OllamaChatRequest) (ChatResult, error) {
+	m.logger.Debug("开始阻塞式聊天", "model", req.Model, "messageCount", len(req.Messages), "toolCount", len(req.Tools))
 
-	requestBody := map[string]interface{}{
-		"model":    model,
-		"messages": messages,
-		"stream":   false,
+	client, err := m.serverClient(req.ServerID)
+	if err != nil {
+		return ChatResult{}, err
 	}
 
-	response, err := m.app.httpClient.Post("/api/chat", core.Options{
+	req.Stream = false
+	response, err := client.Post("/api/chat", core.Options{
 		Headers: map[string]string{"Content-Type": "application/json"},
-		Body:    requestBody,
+		Body:    req,
 	})
 
 	if err != nil {
 		m.logger.Error("阻塞式聊天请求失败", "error", err)
-		return "", err
+		return ChatResult{}, err
 	}
 
 	if err := HandleHTTPError(response.StatusCode, response.Body, m.logger, "阻塞式聊天"); err != nil {
+		return ChatResult{}, err
+	}
+
+	var result ollamaChatResponse
+	if err := UnmarshalJSONWithError([]byte(response.Body), &result, m.logger, "解析聊天响应"); err != nil {
+		return ChatResult{}, err
+	}
+	if result.Error != "" {
+		return ChatResult{}, fmt.Errorf("聊天错误: %s", result.Error)
+	}
+
+	chatResult := ChatResult{
+		Content:   result.Message.Content,
+		Thinking:  result.Message.Thinking,
+		ToolCalls: result.Message.ToolCalls,
+	}
+	// 模型未使用 thinking 字段时, 回退为解析 <think> 标签
+	if chatResult.Thinking == "" && req.Think == nil {
+		chatResult.Thinking, chatResult.Content = NewThinkTagSplitter(m.templatePrefillsThink(client, req.ServerID, req.Model)).Split(chatResult.Content)
+	}
+
+	m.logger.Debug("阻塞式聊天成功", "responseLength", len(chatResult.Content), "thinkingLength", len(chatResult.Thinking), "toolCalls", len(chatResult.ToolCalls))
+	return chatResult, nil
+}
+
+// Generate 实现AIProvider接口的阻塞式补全方法 (/api/generate)
+func (m *ModelManager) Generate(req OllamaGenerateRequest) (string, error) {
+	m.logger.Debug("开始阻塞式补全", "model", req.Model, "promptLength", len(req.Prompt))
+
+	if m.app.httpClient == nil {
+		return "", fmt.Errorf("HTTP客户端未初始化")
+	}
+
+	req.Stream = false
+	response, err := m.app.httpClient.Post("/api/generate", core.Options{
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    req,
+	})
+	if err != nil {
+		m.logger.Error("补全请求失败", "error", err)
+		return "", err
+	}
+	if err := HandleHTTPError(response.StatusCode, response.Body, m.logger, "补全"); err != nil {
 		return "", err
 	}
 
 	var result map[string]interface{}
-	if err := UnmarshalJSONWithError([]byte(response.Body), &result, m.logger, "解析聊天响应"); err != nil {
+	if err := UnmarshalJSONWithError([]byte(response.Body), &result, m.logger, "解析补全响应"); err != nil {
 		return "", err
 	}
+	return ExtractResponseContent(result)
+}
 
-	content, err := ExtractMessageContent(result)
+// Embed 调用 /api/embed 为一组文本生成向量, serverID 为空时使用活动服务器
+// 输入较多时按 embedBatchSize 分批请求, 返回的向量顺序与输入一致
+func (m *ModelManager) Embed(serverID string, model string, inputs []string) ([][]float64, error) {
+	m.logger.Debug("开始生成向量", "serverID", serverID, "model", model, "inputCount", len(inputs))
+	if model == "" {
+		return nil, fmt.Errorf("嵌入模型不能为空")
+	}
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	client, err := m.serverClient(serverID)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	m.logger.Debug("阻塞式聊天成功", "responseLength", len(content))
-	return content, nil
+
+	embeddings := make([][]float64, 0, len(inputs))
+	for start := 0; start < len(inputs); start += embedBatchSize {
+		batch := inputs[start:min(start+embedBatchSize, len(inputs))]
+		vectors, err := m.embedBatch(client, model, batch)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, vectors...)
+	}
+	return embeddings, nil
 }
 
-// ChatStream 实现AIProvider接口的流式聊天方法
-func (m *ModelManager) ChatStream(model string, messages []core.Message, callback func(string)) error {
-	m.logger.Debug("开始流式聊天", "model", model, "messageCount", len(messages))
+// embedBatch 发送单个 /api/embed 请求
+func (m *ModelManager) embedBatch(client *core.HttpCli, model string, inputs []string) ([][]float64, error) {
+	response, err := client.Post("/api/embed", core.Options{
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    map[string]interface{}{"model": model, "input": inputs},
+	})
+	if err != nil {
+		m.logger.Error("向量请求失败", "model", model, "error", err)
+		return nil, err
+	}
+	if err := HandleHTTPError(response.StatusCode, response.Body, m.logger, "生成向量"); err != nil {
+		return nil, err
+	}
 
-	if m.app.httpClient == nil {
-		return fmt.Errorf("HTTP客户端未初始化")
+	var result struct {
+		Embeddings [][]float64 `json:"embeddings"`
+		Error      string      `json:"error"`
+	}
+	if err := UnmarshalJSONWithError([]byte(response.Body), &result, m.logger, "解析向量响应"); err != nil {
+		return nil, err
 	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("生成向量失败: %s", result.Error)
+	}
+	if len(result.Embeddings) != len(inputs) {
+		return nil, fmt.Errorf("向量数量 %d 与输入数量 %d 不一致", len(result.Embeddings), len(inputs))
+	}
+	return result.Embeddings, nil
+}
 
-	requestBody := map[string]interface{}{
-		"model":    model,
-		"messages": messages,
-		"stream":   true,
+// templatePrefillsThink 判断模型的提示词模板是否在回复前预填了 <think>
+// 这类模型 (如部分 deepseek-r1 模板) 只输出结束标签, 需要从回复开头按思考过程解析
+// 结果按服务器地址和模型缓存, 查询失败时按未预填处理并同样缓存, 避免每轮对话都重复请求
+func (m *ModelManager) templatePrefillsThink(client *core.HttpCli, serverID string, model string) bool {
+	key := m.serverBaseURL(serverID) + "|" + model
+	if cached, ok := m.thinkPrefill.Load(key); ok {
+		return cached.(bool)
+	}
+	prefilled := m.fetchTemplatePrefillsThink(client, model)
+	m.thinkPrefill.Store(key, prefilled)
+	return prefilled
+}
+
+func (m *ModelManager) fetchTemplatePrefillsThink(client *core.HttpCli, model string) bool {
+	response, err := client.Post("/api/show", core.Options{
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    map[string]string{"model": model},
+	})
+	if err != nil {
+		m.logger.Warn("获取模型模板失败", "model", model, "error", err)
+		return false
+	}
+	if response.StatusCode >= 400 {
+		m.logger.Warn("获取模型模板失败", "model", model, "statusCode", response.StatusCode)
+		return false
+	}
+
+	var show struct {
+		Template string `json:"template"`
+	}
+	if err := UnmarshalJSONWithError([]byte(response.Body), &show, m.logger, "解析模型信息"); err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimSpace(show.Template), thinkOpenTag)
+}
+
+// serverBaseURL 返回请求实际使用的服务器地址, serverID 为空时为活动服务器的地址
+func (m *ModelManager) serverBaseURL(serverID string) string {
+	var server *types.OllamaServerConfig
+	var err error
+	if serverID == "" {
+		server, err = m.configMgr.GetActiveServer()
+	} else {
+		server, err = m.configMgr.GetServerByID(serverID)
+	}
+	if err != nil {
+		// 与 rebuildDependencies 一致, 没有活动服务器时使用默认地址
+		return "http://localhost:11434"
+	}
+	return strings.TrimRight(EnsureHTTPPrefix(server.BaseURL), "/")
+}
+
+// serverClient 返回指定服务器的 HTTP 客户端, serverID 为空时使用活动服务器的客户端
+func (m *ModelManager) serverClient(serverID string) (*core.HttpCli, error) {
+	if serverID == "" {
+		if m.app.httpClient == nil {
+			return nil, fmt.Errorf("HTTP客户端未初始化")
+		}
+		return m.app.httpClient, nil
+	}
+
+	serverConfig, err := m.configMgr.GetServerByID(serverID)
+	if err != nil {
+		return nil, err
+	}
+	client := core.NewHttp(m.logger.WithPrefix("TempClient"))
+	client.Create(&core.Config{
+		BaseURL: EnsureHTTPPrefix(serverConfig.BaseURL),
+	})
+	return client, nil
+}
+
+// ChatStream 实现AIProvider接口的流式聊天方法
+// 思考过程和正文通过 callback 逐块返回, 完整内容和工具调用在结束后一并返回
+func (m *ModelManager) ChatStream(req OllamaChatRequest, callback func(ChatChunk)) (ChatResult, error) {
+	m.logger.Debug("开始流式聊天", "model", req.Model, "messageCount", len(req.Messages), "toolCount", len(req.Tools))
+
+	client, err := m.serverClient(req.ServerID)
+	if err != nil {
+		return ChatResult{}, err
 	}
 
-	resp, err := m.app.httpClient.PostStream("/api/chat", core.Options{
+	req.Stream = true
+	resp, err := client.PostStream("/api/chat", core.Options{
 		Headers: map[string]string{"Content-Type": "application/json"},
-		Body:    requestBody,
+		Body:    req,
 	})
 
 	if err != nil {
 		m.logger.Error("流式聊天请求失败", "error", err)
-		return err
+		return ChatResult{}, err
 	}
 	defer resp.Body.Close()
 
@@ -379,7 +620,21 @@ func (m *ModelManager) ChatStream(model string, messages []core.Message, callbac
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		errMsg := fmt.Sprintf("流式聊天失败，状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
 		m.logger.Error(errMsg)
-		return fmt.Errorf(errMsg)
+		return ChatResult{}, errors.New(errMsg)
+	}
+
+	var result ChatResult
+	var content, thinking strings.Builder
+	// 显式设置了 think 时思考过程只会出现在 thinking 字段中, 不需要查询模板
+	native := req.Think != nil
+	splitter := newChatStreamSplitter(!native && m.templatePrefillsThink(client, req.ServerID, req.Model), native)
+	emit := func(chunk ChatChunk) {
+		if chunk.Thinking == "" && chunk.Content == "" {
+			return
+		}
+		thinking.WriteString(chunk.Thinking)
+		content.WriteString(chunk.Content)
+		callback(chunk)
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
@@ -389,23 +644,22 @@ func (m *ModelManager) ChatStream(model string, messages []core.Message, callbac
 			continue
 		}
 
-		var streamResponse map[string]interface{}
-		if err := UnmarshalJSONWithError(line, &streamResponse, m.logger, "解析流式响应"); err != nil {
+		var chunk ollamaChatResponse
+		if err := UnmarshalJSONWithError(line, &chunk, m.logger, "解析流式响应"); err != nil {
 			continue
 		}
 
-		if errorMsg, ok := streamResponse["error"]; ok {
-			m.logger.Error("流式聊天过程中出现错误", "error", errorMsg)
-			return fmt.Errorf("聊天错误: %v", errorMsg)
+		if chunk.Error != "" {
+			m.logger.Error("流式聊天过程中出现错误", "error", chunk.Error)
+			return ChatResult{}, fmt.Errorf("聊天错误: %s", chunk.Error)
 		}
 
-		if message, ok := streamResponse["message"].(map[string]interface{}); ok {
-			if content, ok := message["content"].(string); ok && content != "" {
-				callback(content)
-			}
-		}
+		chunkThinking, chunkContent := splitter.Feed(chunk.Message.Thinking, chunk.Message.Content)
+		emit(ChatChunk{Thinking: chunkThinking})
+		emit(ChatChunk{Content: chunkContent})
+		result.ToolCalls = append(result.ToolCalls, chunk.Message.ToolCalls...)
 
-		if done, ok := streamResponse["done"].(bool); ok && done {
+		if chunk.Done {
 			m.logger.Debug("流式聊天完成")
 			break
 		}
@@ -413,9 +667,14 @@ func (m *ModelManager) ChatStream(model string, messages []core.Message, callbac
 
 	if err := scanner.Err(); err != nil {
 		m.logger.Error("读取流式响应失败", "error", err)
-		return fmt.Errorf("读取流式响应失败: %v", err)
+		return ChatResult{}, fmt.Errorf("读取流式响应失败: %v", err)
 	}
 
-	m.logger.Debug("流式聊天成功完成")
-	return nil
+	restThinking, restContent := splitter.Flush()
+	emit(ChatChunk{Thinking: restThinking, Content: restContent})
+
+	result.Content = content.String()
+	result.Thinking = strings.TrimSpace(thinking.String())
+	m.logger.Debug("流式聊天成功完成", "thinkingLength", len(result.Thinking), "toolCalls", len(result.ToolCalls))
+	return result, nil
 }
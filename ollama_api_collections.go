@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+)
+
+// apiCollectionsKey API 调试器请求集合的哈希键, 字段为集合ID
+const apiCollectionsKey = "api_collections"
+
+// apiVariablePattern 匹配请求中的 {{变量}} 占位符
+var apiVariablePattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// ListApiCollections 获取所有请求集合, 按名称排序
+func (d *OllamaApiDebugger) ListApiCollections() ([]types.ApiCollection, error) {
+	dataMap, err := d.store.HGetAll(apiCollectionsKey)
+	if err != nil {
+		d.logger.Error("获取请求集合失败", "error", err)
+		return nil, fmt.Errorf("获取请求集合失败: %w", err)
+	}
+	collections := make([]types.ApiCollection, 0, len(dataMap))
+	for _, data := range dataMap {
+		var collection types.ApiCollection
+		if err := UnmarshalJSONWithError([]byte(data), &collection, d.logger, "解析请求集合"); err != nil {
+			continue
+		}
+		collections = append(collections, collection)
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		return strings.ToLower(collections[i].Name) < strings.ToLower(collections[j].Name)
+	})
+	return collections, nil
+}
+
+// GetApiCollection 获取一个请求集合
+func (d *OllamaApiDebugger) GetApiCollection(id string) (types.ApiCollection, error) {
+	data, err := d.store.HGet(apiCollectionsKey, id)
+	if err != nil || data == "" {
+		return types.ApiCollection{}, fmt.Errorf("请求集合不存在: %s", id)
+	}
+	var collection types.ApiCollection
+	if err := UnmarshalJSONWithError([]byte(data), &collection, d.logger, "解析请求集合"); err != nil {
+		return types.ApiCollection{}, err
+	}
+	return collection, nil
+}
+
+// SaveApiCollection 创建或更新请求集合, ID 为空时创建新集合
+func (d *OllamaApiDebugger) SaveApiCollection(collection types.ApiCollection) (types.ApiCollection, error) {
+	collection.Name = strings.TrimSpace(collection.Name)
+	if collection.Name == "" {
+		return types.ApiCollection{}, fmt.Errorf("集合名称不能为空")
+	}
+
+	now := GetCurrentTimestamp()
+	if collection.ID == "" {
+		collection.ID = GenerateUniqueID()
+		collection.CreatedAt = now
+	} else if existing, err := d.GetApiCollection(collection.ID); err == nil {
+		collection.CreatedAt = existing.CreatedAt
+	} else if collection.CreatedAt == 0 {
+		collection.CreatedAt = now
+	}
+	collection.UpdatedAt = now
+
+	for i := range collection.Requests {
+		request := &collection.Requests[i]
+		if request.ID == "" {
+			request.ID = GenerateUniqueID()
+		}
+		if request.CreatedAt == 0 {
+			request.CreatedAt = now
+		}
+		if request.UpdatedAt == 0 {
+			request.UpdatedAt = now
+		}
+		request.Folder = normalizeApiFolder(request.Folder)
+		request.Request.CollectionID = collection.ID
+	}
+	normalizeApiFolders(&collection)
+	normalizeApiVariables(&collection)
+
+	if err := d.storeApiCollection(collection); err != nil {
+		return types.ApiCollection{}, err
+	}
+	d.logger.Info("请求集合已保存", "id", collection.ID, "name", collection.Name, "requests", len(collection.Requests))
+	return collection, nil
+}
+
+// DeleteApiCollection 删除请求集合
+func (d *OllamaApiDebugger) DeleteApiCollection(id string) error {
+	if err := d.store.HDel(apiCollectionsKey, id); err != nil {
+		d.logger.Error("删除请求集合失败", "id", id, "error", err)
+		return fmt.Errorf("删除请求集合失败: %w", err)
+	}
+	d.logger.Info("请求集合已删除", "id", id)
+	return nil
+}
+
+// SaveApiRequest 把请求保存到集合中, ID 为空时新增, 否则更新同ID的请求
+func (d *OllamaApiDebugger) SaveApiRequest(collectionID string, saved types.ApiSavedRequest) (types.ApiSavedRequest, error) {
+	saved.Name = strings.TrimSpace(saved.Name)
+	if saved.Name == "" {
+		return types.ApiSavedRequest{}, fmt.Errorf("请求名称不能为空")
+	}
+	collection, err := d.GetApiCollection(collectionID)
+	if err != nil {
+		return types.ApiSavedRequest{}, err
+	}
+
+	now := GetCurrentTimestamp()
+	saved.Folder = normalizeApiFolder(saved.Folder)
+	saved.Request.CollectionID = collection.ID
+	saved.UpdatedAt = now
+
+	index := -1
+	for i, request := range collection.Requests {
+		if saved.ID != "" && request.ID == saved.ID {
+			index = i
+			break
+		}
+	}
+	if index >= 0 {
+		saved.CreatedAt = collection.Requests[index].CreatedAt
+		collection.Requests[index] = saved
+	} else {
+		if saved.ID == "" {
+			saved.ID = GenerateUniqueID()
+		}
+		saved.CreatedAt = now
+		collection.Requests = append(collection.Requests, saved)
+	}
+	normalizeApiFolders(&collection)
+	collection.UpdatedAt = now
+
+	if err := d.storeApiCollection(collection); err != nil {
+		return types.ApiSavedRequest{}, err
+	}
+	d.logger.Debug("请求已保存到集合", "collectionId", collectionID, "requestId", saved.ID, "folder", saved.Folder)
+	return saved, nil
+}
+
+// DeleteApiRequest 从集合中删除一个请求
+func (d *OllamaApiDebugger) DeleteApiRequest(collectionID string, requestID string) error {
+	collection, err := d.GetApiCollection(collectionID)
+	if err != nil {
+		return err
+	}
+	requests := collection.Requests[:0]
+	for _, request := range collection.Requests {
+		if request.ID != requestID {
+			requests = append(requests, request)
+		}
+	}
+	if len(requests) == len(collection.Requests) {
+		return fmt.Errorf("请求不存在: %s", requestID)
+	}
+	collection.Requests = requests
+	collection.UpdatedAt = GetCurrentTimestamp()
+	return d.storeApiCollection(collection)
+}
+
+// DeleteApiFolder 删除集合中的文件夹, 文件夹下的子文件夹和请求一并删除
+func (d *OllamaApiDebugger) DeleteApiFolder(collectionID string, folder string) error {
+	folder = normalizeApiFolder(folder)
+	if folder == "" {
+		return fmt.Errorf("文件夹路径不能为空")
+	}
+	collection, err := d.GetApiCollection(collectionID)
+	if err != nil {
+		return err
+	}
+
+	requests := make([]types.ApiSavedRequest, 0, len(collection.Requests))
+	for _, request := range collection.Requests {
+		if !inApiFolder(request.Folder, folder) {
+			requests = append(requests, request)
+		}
+	}
+	folders := make([]string, 0, len(collection.Folders))
+	for _, path := range collection.Folders {
+		if !inApiFolder(path, folder) {
+			folders = append(folders, path)
+		}
+	}
+	collection.Requests = requests
+	collection.Folders = folders
+	collection.UpdatedAt = GetCurrentTimestamp()
+	return d.storeApiCollection(collection)
+}
+
+// resolveApiVariables 用请求所属集合的变量替换请求中的 {{变量}}, 未定义的变量保持原样
+func (d *OllamaApiDebugger) resolveApiVariables(request types.ApiRequest) types.ApiRequest {
+	if request.CollectionID == "" || d.store == nil {
+		return request
+	}
+	collection, err := d.GetApiCollection(request.CollectionID)
+	if err != nil || len(collection.Variables) == 0 {
+		return request
+	}
+	variables := make(map[string]string, len(collection.Variables))
+	for _, variable := range collection.Variables {
+		variables[variable.Key] = variable.Value
+	}
+	return substituteApiVariables(request, variables)
+}
+
+// substituteApiVariables 替换请求路径、查询参数、请求头和请求体中的变量
+func substituteApiVariables(request types.ApiRequest, variables map[string]string) types.ApiRequest {
+	replace := func(text string) string {
+		return replaceApiVariables(text, variables)
+	}
+	request.Path = replace(request.Path)
+	request.QueryParams = append([]types.QueryParam(nil), request.QueryParams...)
+	for i := range request.QueryParams {
+		request.QueryParams[i].Key = replace(request.QueryParams[i].Key)
+		request.QueryParams[i].Value = replace(request.QueryParams[i].Value)
+	}
+	request.Headers = append([]types.RequestHeader(nil), request.Headers...)
+	for i := range request.Headers {
+		request.Headers[i].Key = replace(request.Headers[i].Key)
+		request.Headers[i].Value = replace(request.Headers[i].Value)
+	}
+	request.Body.RawContent = replace(request.Body.RawContent)
+	request.Body.FormData = append([]types.FormDataItem(nil), request.Body.FormData...)
+	for i := range request.Body.FormData {
+		request.Body.FormData[i].Key = replace(request.Body.FormData[i].Key)
+		request.Body.FormData[i].Value = replace(request.Body.FormData[i].Value)
+	}
+	return request
+}
+
+func replaceApiVariables(text string, variables map[string]string) string {
+	if len(variables) == 0 || !strings.Contains(text, "{{") {
+		return text
+	}
+	return apiVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := apiVariablePattern.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+func (d *OllamaApiDebugger) storeApiCollection(collection types.ApiCollection) error {
+	data, err := MarshalJSONWithError(collection, d.logger, "序列化请求集合")
+	if err != nil {
+		return err
+	}
+	if err := d.store.HSet(apiCollectionsKey, collection.ID, string(data)); err != nil {
+		d.logger.Error("保存请求集合失败", "id", collection.ID, "error", err)
+		return fmt.Errorf("保存请求集合失败: %w", err)
+	}
+	return nil
+}
+
+// normalizeApiFolder 规范化文件夹路径: 去掉多余的斜杠和空白, 如 " /a//b/ " -> "a/b"
+func normalizeApiFolder(folder string) string {
+	parts := strings.Split(folder, "/")
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// normalizeApiFolders 规范化集合的文件夹列表, 补全请求所在的文件夹及其上级文件夹, 去重后排序
+func normalizeApiFolders(collection *types.ApiCollection) {
+	seen := make(map[string]bool)
+	add := func(folder string) {
+		folder = normalizeApiFolder(folder)
+		for folder != "" && !seen[folder] {
+			seen[folder] = true
+			index := strings.LastIndex(folder, "/")
+			if index < 0 {
+				break
+			}
+			folder = folder[:index]
+		}
+	}
+	for _, folder := range collection.Folders {
+		add(folder)
+	}
+	for _, request := range collection.Requests {
+		add(request.Folder)
+	}
+
+	collection.Folders = make([]string, 0, len(seen))
+	for folder := range seen {
+		collection.Folders = append(collection.Folders, folder)
+	}
+	sort.Strings(collection.Folders)
+	if collection.Requests == nil {
+		collection.Requests = []types.ApiSavedRequest{}
+	}
+}
+
+// normalizeApiVariables 去掉变量名两侧的空白, 丢弃空变量名, 同名变量保留最后一个
+func normalizeApiVariables(collection *types.ApiCollection) {
+	index := make(map[string]int)
+	variables := make([]types.ApiVariable, 0, len(collection.Variables))
+	for _, variable := range collection.Variables {
+		variable.Key = strings.TrimSpace(variable.Key)
+		if variable.Key == "" {
+			continue
+		}
+		if i, ok := index[variable.Key]; ok {
+			variables[i] = variable
+			continue
+		}
+		index[variable.Key] = len(variables)
+		variables = append(variables, variable)
+	}
+	collection.Variables = variables
+}
+
+// inApiFolder 判断路径是否为 folder 本身或其子路径
+func inApiFolder(path string, folder string) bool {
+	return path == folder || strings.HasPrefix(path, folder+"/")
+}
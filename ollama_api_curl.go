@@ -0,0 +1,683 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"go/format"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+)
+
+// curlValueOptions 需要参数的 curl 选项, 短选项映射为对应的长选项
+// 列表需要完整: 漏掉的选项会把它的参数误当作请求地址, 例如 "curl -D - http://host" 中的 "-"
+var curlValueOptions = map[string]string{
+	"-A": "--user-agent", "-b": "--cookie", "-c": "--cookie-jar", "-C": "--continue-at", "-d": "--data",
+	"-D": "--dump-header", "-e": "--referer", "-E": "--cert", "-F": "--form", "-H": "--header",
+	"-K": "--config", "-m": "--max-time", "-o": "--output", "-P": "--ftp-port", "-Q": "--quote",
+	"-r": "--range", "-t": "--telnet-option", "-T": "--upload-file", "-u": "--user", "-U": "--proxy-user",
+	"-w": "--write-out", "-x": "--proxy", "-X": "--request", "-y": "--speed-time", "-Y": "--speed-limit",
+	"-z": "--time-cond",
+
+	"--request": "--request", "--header": "--header", "--data": "--data", "--data-raw": "--data-raw",
+	"--data-binary": "--data-binary", "--data-ascii": "--data-ascii", "--data-urlencode": "--data-urlencode",
+	"--json": "--json", "--form": "--form", "--form-string": "--form-string", "--user": "--user",
+	"--user-agent": "--user-agent", "--referer": "--referer", "--cookie": "--cookie", "--url": "--url",
+	"--oauth2-bearer": "--oauth2-bearer", "--upload-file": "--upload-file",
+
+	"--abstract-unix-socket": "", "--alt-svc": "", "--aws-sigv4": "", "--cacert": "", "--capath": "",
+	"--cert": "", "--cert-type": "", "--ciphers": "", "--config": "", "--connect-timeout": "",
+	"--connect-to": "", "--continue-at": "", "--cookie-jar": "", "--create-file-mode": "", "--crlfile": "",
+	"--curves": "", "--delegation": "", "--dns-interface": "", "--dns-ipv4-addr": "", "--dns-ipv6-addr": "",
+	"--dns-servers": "", "--doh-url": "", "--dump-header": "", "--ech": "", "--egd-file": "", "--engine": "",
+	"--etag-compare": "", "--etag-save": "", "--expect100-timeout": "", "--ftp-account": "",
+	"--ftp-alternative-to-user": "", "--ftp-method": "", "--ftp-port": "", "--ftp-ssl-ccc-mode": "",
+	"--happy-eyeballs-timeout-ms": "", "--haproxy-clientip": "", "--hostpubmd5": "", "--hostpubsha256": "",
+	"--hsts": "", "--interface": "", "--ip-tos": "", "--ipfs-gateway": "", "--keepalive-cnt": "",
+	"--keepalive-time": "", "--key": "", "--key-type": "", "--krb": "", "--libcurl": "", "--limit-rate": "",
+	"--local-port": "", "--login-options": "", "--mail-auth": "", "--mail-from": "", "--mail-rcpt": "",
+	"--max-filesize": "", "--max-redirs": "", "--max-time": "", "--netrc-file": "", "--noproxy": "",
+	"--output": "", "--output-dir": "", "--parallel-max": "", "--pass": "", "--pinnedpubkey": "",
+	"--preproxy": "", "--proto": "", "--proto-default": "", "--proto-redir": "", "--proxy": "",
+	"--proxy-cacert": "", "--proxy-capath": "", "--proxy-cert": "", "--proxy-cert-type": "",
+	"--proxy-ciphers": "", "--proxy-crlfile": "", "--proxy-header": "", "--proxy-key": "",
+	"--proxy-key-type": "", "--proxy-pass": "", "--proxy-pinnedpubkey": "", "--proxy-service-name": "",
+	"--proxy-tls13-ciphers": "", "--proxy-tlsauthtype": "", "--proxy-tlspassword": "", "--proxy-tlsuser": "",
+	"--proxy-user": "", "--proxy1.0": "", "--pubkey": "", "--quote": "", "--random-file": "", "--range": "",
+	"--rate": "", "--request-target": "", "--resolve": "", "--retry": "", "--retry-delay": "",
+	"--retry-max-time": "", "--sasl-authzid": "", "--service-name": "", "--socks4": "", "--socks4a": "",
+	"--socks5": "", "--socks5-gssapi-service": "", "--socks5-hostname": "", "--speed-limit": "",
+	"--speed-time": "", "--stderr": "", "--telnet-option": "", "--tftp-blksize": "", "--time-cond": "",
+	"--tls-max": "", "--tls13-ciphers": "", "--tlsauthtype": "", "--tlspassword": "", "--tlsuser": "",
+	"--trace": "", "--trace-ascii": "", "--trace-config": "", "--unix-socket": "", "--url-query": "",
+	"--variable": "", "--write-out": "",
+}
+
+// curlFlagOptions 常用的不带参数的长选项, 用于判断未知选项后面的参数归属
+var curlFlagOptions = map[string]bool{
+	"--anyauth": true, "--append": true, "--basic": true, "--compressed": true, "--create-dirs": true,
+	"--crlf": true, "--digest": true, "--disable": true, "--fail": true, "--fail-early": true,
+	"--fail-with-body": true, "--get": true, "--globoff": true, "--head": true, "--http0.9": true,
+	"--http1.0": true, "--http1.1": true, "--http2": true, "--http2-prior-knowledge": true, "--http3": true,
+	"--http3-only": true, "--include": true, "--insecure": true, "--ipv4": true, "--ipv6": true,
+	"--junk-session-cookies": true, "--list-only": true, "--location": true, "--location-trusted": true,
+	"--negotiate": true, "--netrc": true, "--netrc-optional": true, "--next": true, "--ntlm": true,
+	"--parallel": true, "--parallel-immediate": true, "--path-as-is": true, "--post301": true,
+	"--post302": true, "--post303": true, "--progress-bar": true, "--proxy-insecure": true, "--raw": true,
+	"--remote-header-name": true, "--remote-name": true, "--remote-name-all": true, "--remote-time": true,
+	"--retry-all-errors": true, "--retry-connrefused": true, "--show-error": true, "--silent": true,
+	"--ssl": true, "--ssl-no-revoke": true, "--ssl-reqd": true, "--styled-output": true,
+	"--suppress-connect-headers": true, "--tcp-fastopen": true, "--tcp-nodelay": true, "--tlsv1": true,
+	"--tlsv1.0": true, "--tlsv1.1": true, "--tlsv1.2": true, "--tlsv1.3": true, "--tr-encoding": true,
+	"--verbose": true, "--xattr": true,
+}
+
+// shellSafeWord 不需要加引号的命令行参数
+var shellSafeWord = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// ParseCurlCommand 把 curl 命令解析为调试请求
+// 支持 -X、-H、-d/--data-raw/--data-binary/--data-urlencode/--json、-F、-G、-I、-u、--url-query 等常用选项,
+// URL 与已配置服务器的基础地址匹配时自动选中该服务器, 路径为去掉基础地址后的部分
+// 调试器无法原样发送的内容 (如 -F 的 multipart 表单) 会转换后记录在 Warnings 中
+func (d *OllamaApiDebugger) ParseCurlCommand(command string) (types.CurlImportResult, error) {
+	parsed, err := parseCurlCommandLine(command)
+	if err != nil {
+		return types.CurlImportResult{}, err
+	}
+	request, err := parsed.toApiRequest()
+	if err != nil {
+		return types.CurlImportResult{}, err
+	}
+	request.SelectedServerID, request.Path = d.matchServerURL(parsed.url)
+	if request.SelectedServerID == "" {
+		d.logger.Warn("curl 命令中的地址没有匹配的服务器", "url", parsed.url)
+	}
+	d.logger.Debug("已解析 curl 命令", "method", request.Method, "path", request.Path, "serverID", request.SelectedServerID, "warnings", len(parsed.warnings))
+	return types.CurlImportResult{Request: request, Warnings: append([]string{}, parsed.warnings...)}, nil
+}
+
+// curlCommand curl 命令中与请求相关的部分
+type curlCommand struct {
+	method  string
+	url     string
+	headers []types.RequestHeader
+	data    []string
+	form    []types.FormDataItem
+	query   []string // --url-query 追加的查询参数, 已按 URL 编码
+	get     bool     // -G: 把 -d 的内容作为查询参数
+	head    bool
+
+	warnings []string
+}
+
+// parseCurlCommandLine 拆分命令行并解析选项, 开头的 curl 可以省略
+func parseCurlCommandLine(command string) (*curlCommand, error) {
+	words, err := splitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 && (words[0] == "curl" || strings.HasSuffix(words[0], "/curl") || strings.EqualFold(words[0], "curl.exe")) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("curl 命令为空")
+	}
+	return parseCurlWords(words)
+}
+
+func parseCurlWords(words []string) (*curlCommand, error) {
+	cmd := &curlCommand{}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "-" {
+			continue
+		}
+		if !strings.HasPrefix(word, "-") {
+			if cmd.url == "" {
+				cmd.url = word
+			}
+			continue
+		}
+
+		name, value, hasValue := word, "", false
+		if strings.HasPrefix(word, "--") {
+			name, value, hasValue = strings.Cut(word, "=")
+			// --expand-data 等选项与原选项相同, 只是参数中的 {{变量}} 由 --variable 展开, 这里保留原样
+			if strings.HasPrefix(name, "--expand-") {
+				name = "--" + strings.TrimPrefix(name, "--expand-")
+			}
+		} else if len(word) > 2 {
+			// -sSL 这样的组合选项, 以及 -XPOST、-sX POST 这样参数紧跟或位于下一个参数的短选项
+			name = ""
+			for j := 1; j < len(word); j++ {
+				short := "-" + word[j:j+1]
+				if _, ok := curlValueOptions[short]; ok {
+					name, value, hasValue = short, word[j+1:], j+1 < len(word)
+					break
+				}
+				cmd.applyFlag(short)
+			}
+			if name == "" {
+				continue
+			}
+		}
+
+		option, takesValue := curlValueOptions[name]
+		if !takesValue {
+			// 无法确定未知长选项是否带参数时报错, 而不是把它的参数误当作请求地址
+			if !hasValue && strings.HasPrefix(name, "--") && !curlFlagOptions[name] && !strings.HasPrefix(name, "--no-") &&
+				i+1 < len(words) && !strings.HasPrefix(words[i+1], "-") {
+				return nil, fmt.Errorf("无法识别的 curl 选项 %s, 无法判断其后的 %q 是否为它的参数", name, words[i+1])
+			}
+			cmd.applyFlag(name)
+			continue
+		}
+		if option == "" {
+			option = name
+		}
+		if !hasValue {
+			if i+1 >= len(words) {
+				return nil, fmt.Errorf("curl 选项 %s 缺少参数", name)
+			}
+			i++
+			value = words[i]
+		}
+		if err := cmd.applyOption(option, value); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.url == "" {
+		return nil, fmt.Errorf("curl 命令中没有请求地址")
+	}
+	return cmd, nil
+}
+
+// applyFlag 处理不带参数的选项, 与请求无关的选项直接忽略
+func (c *curlCommand) applyFlag(flag string) {
+	switch flag {
+	case "-G", "--get":
+		c.get = true
+	case "-I", "--head":
+		c.head = true
+	}
+}
+
+func (c *curlCommand) applyOption(option string, value string) error {
+	switch option {
+	case "--request":
+		c.method = strings.ToUpper(value)
+	case "--url":
+		c.url = value
+	case "--header":
+		key, headerValue, found := strings.Cut(value, ":")
+		if !found {
+			// "X-Empty;" 表示发送空值的请求头, 没有冒号和分号的写法在 curl 中表示删除默认请求头
+			if strings.HasSuffix(value, ";") {
+				c.addHeader(strings.TrimSuffix(value, ";"), "")
+			}
+			return nil
+		}
+		c.addHeader(strings.TrimSpace(key), strings.TrimSpace(headerValue))
+	case "--data", "--data-ascii", "--data-binary":
+		if strings.HasPrefix(value, "@") {
+			return fmt.Errorf("不支持从文件读取请求体: %s", value)
+		}
+		c.data = append(c.data, value)
+	case "--data-raw":
+		c.data = append(c.data, value)
+	case "--data-urlencode":
+		encoded, err := curlURLEncode(value)
+		if err != nil {
+			return err
+		}
+		c.data = append(c.data, encoded)
+	case "--json":
+		if strings.HasPrefix(value, "@") {
+			return fmt.Errorf("不支持从文件读取请求体: %s", value)
+		}
+		c.data = append(c.data, value)
+		c.setDefaultHeader("Content-Type", string(types.RawBodyContentTypeJson))
+		c.setDefaultHeader("Accept", string(types.RawBodyContentTypeJson))
+	case "--form", "--form-string":
+		key, fieldValue, found := strings.Cut(value, "=")
+		if !found {
+			return fmt.Errorf("无效的表单字段: %s", value)
+		}
+		if option == "--form" {
+			if strings.HasPrefix(fieldValue, "@") || strings.HasPrefix(fieldValue, "<") {
+				return fmt.Errorf("不支持上传文件的表单字段: %s", key)
+			}
+			fieldValue, _, _ = strings.Cut(fieldValue, ";type=")
+		}
+		if len(c.form) == 0 {
+			c.warnings = append(c.warnings, "-F 的 multipart/form-data 表单已转换为 application/x-www-form-urlencoded 表单发送, 只接受 multipart 的接口可能无法处理")
+		}
+		c.form = append(c.form, types.FormDataItem{Key: key, Value: fieldValue})
+	case "--url-query":
+		// 以 + 开头的参数已经编码, 原样追加
+		if strings.HasPrefix(value, "+") {
+			c.query = append(c.query, value[1:])
+			return nil
+		}
+		encoded, err := curlURLEncode(value)
+		if err != nil {
+			return err
+		}
+		c.query = append(c.query, encoded)
+	case "--user":
+		c.setDefaultHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(value)))
+	case "--oauth2-bearer":
+		c.setDefaultHeader("Authorization", "Bearer "+value)
+	case "--user-agent":
+		c.setDefaultHeader("User-Agent", value)
+	case "--referer":
+		c.setDefaultHeader("Referer", value)
+	case "--cookie":
+		// 不含 = 时参数是 cookie 文件名
+		if strings.Contains(value, "=") {
+			c.addHeader("Cookie", value)
+		}
+	case "--upload-file":
+		return fmt.Errorf("不支持上传文件: %s", value)
+	}
+	return nil
+}
+
+func (c *curlCommand) addHeader(key, value string) {
+	c.headers = append(c.headers, types.RequestHeader{Key: key, Value: value, Enabled: true})
+}
+
+// setDefaultHeader 仅在没有同名请求头时添加
+func (c *curlCommand) setDefaultHeader(key, value string) {
+	if c.header(key) == "" {
+		c.addHeader(key, value)
+	}
+}
+
+func (c *curlCommand) header(key string) string {
+	for i := len(c.headers) - 1; i >= 0; i-- {
+		if strings.EqualFold(c.headers[i].Key, key) {
+			return c.headers[i].Value
+		}
+	}
+	return ""
+}
+
+// toApiRequest 生成请求的方法、查询参数、请求头和请求体, 服务器和路径由调用方根据 URL 匹配
+func (c *curlCommand) toApiRequest() (types.ApiRequest, error) {
+	request := types.ApiRequest{
+		Method:      c.method,
+		QueryParams: []types.QueryParam{},
+		Headers:     append([]types.RequestHeader{}, c.headers...),
+		Body:        types.RequestBody{Type: types.RequestBodyTypeNone},
+	}
+
+	rawURL, _, _ := strings.Cut(c.url, "#")
+	if _, rawQuery, found := strings.Cut(rawURL, "?"); found {
+		request.QueryParams = append(request.QueryParams, parseRawQueryParams(rawQuery)...)
+	}
+	if len(c.query) > 0 {
+		request.QueryParams = append(request.QueryParams, parseRawQueryParams(strings.Join(c.query, "&"))...)
+	}
+
+	data := strings.Join(c.data, "&")
+	switch {
+	case len(c.form) > 0 && len(c.data) > 0:
+		return types.ApiRequest{}, fmt.Errorf("不能同时使用 -d 和 -F")
+	case len(c.form) > 0:
+		request.Body = types.RequestBody{Type: types.RequestBodyTypeFormData, FormData: c.form}
+	case len(c.data) > 0 && c.get:
+		request.QueryParams = append(request.QueryParams, parseRawQueryParams(data)...)
+	case len(c.data) > 0:
+		request.Body = curlBody(data, c.header("Content-Type"))
+	}
+
+	if request.Method == "" {
+		switch {
+		case c.head:
+			request.Method = "HEAD"
+		case request.Body.Type != types.RequestBodyTypeNone:
+			request.Method = "POST"
+		default:
+			request.Method = "GET"
+		}
+	}
+	return request, nil
+}
+
+// curlBody 根据 Content-Type 转换 -d 的内容; 没有指定时按 curl 的默认行为视为表单, 但 JSON 内容保留为原始请求体
+func curlBody(data string, contentType string) types.RequestBody {
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	trimmed := strings.TrimSpace(data)
+	isForm := strings.HasPrefix(contentType, "application/x-www-form-urlencoded")
+	if contentType == "" {
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			return types.RequestBody{Type: types.RequestBodyTypeRaw, RawContent: data, RawContentType: types.RawBodyContentTypeJson}
+		}
+		isForm = true
+		for _, pair := range strings.Split(data, "&") {
+			if pair != "" && !strings.Contains(pair, "=") {
+				isForm = false
+				break
+			}
+		}
+	}
+	if isForm {
+		items := []types.FormDataItem{}
+		for _, param := range parseRawQueryParams(data) {
+			items = append(items, types.FormDataItem{Key: param.Key, Value: param.Value})
+		}
+		return types.RequestBody{Type: types.RequestBodyTypeFormData, FormData: items}
+	}
+
+	rawType := types.RawBodyContentTypeText
+	for _, candidate := range []types.RawBodyContentType{types.RawBodyContentTypeJson, types.RawBodyContentTypeHtml, types.RawBodyContentTypeXml} {
+		if strings.HasPrefix(contentType, string(candidate)) {
+			rawType = candidate
+		}
+	}
+	return types.RequestBody{Type: types.RequestBodyTypeRaw, RawContent: data, RawContentType: rawType}
+}
+
+// curlURLEncode 按 --data-urlencode 和 --url-query 的规则编码: "content"、"=content"、"name=content"
+func curlURLEncode(value string) (string, error) {
+	name, content, found := strings.Cut(value, "=")
+	if !found {
+		// "@filename" 和 "name@filename" 都表示从文件读取
+		if strings.Contains(value, "@") {
+			return "", fmt.Errorf("不支持从文件读取请求体: %s", value)
+		}
+		return url.QueryEscape(value), nil
+	}
+	if strings.Contains(name, "@") {
+		return "", fmt.Errorf("不支持从文件读取请求体: %s", value)
+	}
+	if name == "" {
+		return url.QueryEscape(content), nil
+	}
+	return name + "=" + url.QueryEscape(content), nil
+}
+
+// matchServerURL 找到基础地址与 URL 前缀匹配的服务器 (最长匹配), 返回服务器ID和去掉基础地址后的路径
+// 没有匹配时服务器ID为空, 路径为 URL 中的完整路径
+func (d *OllamaApiDebugger) matchServerURL(rawURL string) (string, string) {
+	rawURL, _, _ = strings.Cut(rawURL, "#")
+	rawURL, _, _ = strings.Cut(rawURL, "?")
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	servers, _ := d.configMgr.GetServers()
+	serverID, matched := "", ""
+	for _, server := range servers {
+		base := strings.TrimRight(server.BaseURL, "/")
+		if base == "" || len(base) <= len(matched) || len(rawURL) < len(base) || !strings.EqualFold(rawURL[:len(base)], base) {
+			continue
+		}
+		if rest := rawURL[len(base):]; rest == "" || strings.HasPrefix(rest, "/") {
+			serverID, matched = server.ID, base
+		}
+	}
+
+	path := rawURL[len(matched):]
+	if matched == "" {
+		_, rest, _ := strings.Cut(rawURL, "://")
+		_, path, _ = strings.Cut(rest, "/")
+		path = "/" + path
+	}
+	if path == "" {
+		path = "/"
+	}
+	return serverID, path
+}
+
+// splitShellWords 按 POSIX shell 的规则拆分命令行, 支持单引号、双引号、$'...'、反斜杠转义和续行
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+					i++
+				}
+				// 续行只起连接作用
+				if runes[i] != '\n' {
+					word.WriteRune(runes[i])
+					inWord = true
+				}
+			}
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("命令中的单引号没有闭合")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, err := readANSIQuoted(runes, i+2, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = end
+		case r == '"':
+			end, err := readDoubleQuoted(runes, i+1, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = end
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func indexRune(runes []rune, start int, target rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// readDoubleQuoted 读取双引号中的内容, 反斜杠只转义 $ ` " \ 和换行, 返回闭合引号的位置
+func readDoubleQuoted(runes []rune, start int, word *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 < len(runes) {
+				switch next := runes[i+1]; next {
+				case '$', '`', '"', '\\':
+					word.WriteRune(next)
+					i++
+					continue
+				case '\n':
+					i++
+					continue
+				}
+			}
+			word.WriteRune(r)
+		default:
+			word.WriteRune(r)
+		}
+	}
+	return 0, fmt.Errorf("命令中的双引号没有闭合")
+}
+
+// readANSIQuoted 读取 $'...' 中的内容并处理 C 风格转义, 返回闭合引号的位置
+func readANSIQuoted(runes []rune, start int, word *strings.Builder) (int, error) {
+	escapes := map[rune]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", '0': "\x00", 'e': "\x1b", 'a': "\a", 'b': "\b", 'f': "\f", 'v': "\v"}
+	for i := start; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\'' {
+			return i, nil
+		}
+		if r != '\\' || i+1 >= len(runes) {
+			word.WriteRune(r)
+			continue
+		}
+		i++
+		next := runes[i]
+		if value, ok := escapes[next]; ok {
+			word.WriteString(value)
+			continue
+		}
+		if next == 'x' || next == 'u' {
+			size := map[rune]int{'x': 2, 'u': 4}[next]
+			end := i + 1
+			for end < len(runes) && end < i+1+size && strings.ContainsRune("0123456789abcdefABCDEF", runes[end]) {
+				end++
+			}
+			if code, err := strconv.ParseUint(string(runes[i+1:end]), 16, 32); err == nil {
+				word.WriteRune(rune(code))
+				i = end - 1
+				continue
+			}
+		}
+		word.WriteRune('\\')
+		word.WriteRune(next)
+	}
+	return 0, fmt.Errorf("命令中的 $'...' 没有闭合")
+}
+
+// GenerateApiSnippets 把调试请求渲染为 curl、HTTPie 命令和 Go net/http 代码, 集合变量会先被替换
+func (d *OllamaApiDebugger) GenerateApiSnippets(request types.ApiRequest) (types.ApiCodeSnippets, error) {
+	request = d.resolveApiVariables(request)
+	req, finalURL, err := d.buildHttpRequest(context.Background(), request)
+	if err != nil {
+		return types.ApiCodeSnippets{}, err
+	}
+	body, _, hasBody := encodeRequestBody(request.Body)
+
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var headers [][2]string
+	for _, key := range keys {
+		for _, value := range req.Header[key] {
+			headers = append(headers, [2]string{key, value})
+		}
+	}
+
+	goCode, err := renderGoSnippet(req.Method, finalURL, headers, body, hasBody)
+	if err != nil {
+		d.logger.Error("生成 Go 代码失败", "error", err)
+		return types.ApiCodeSnippets{}, fmt.Errorf("生成 Go 代码失败: %w", err)
+	}
+	return types.ApiCodeSnippets{
+		URL:    finalURL,
+		Curl:   renderCurlSnippet(req.Method, finalURL, headers, body, hasBody),
+		HTTPie: renderHTTPieSnippet(req.Method, finalURL, headers, body, hasBody),
+		Go:     goCode,
+	}, nil
+}
+
+func renderCurlSnippet(method, target string, headers [][2]string, body string, hasBody bool) string {
+	parts := []string{"curl"}
+	switch {
+	case method == "HEAD":
+		parts = append(parts, "--head")
+	case method != "GET" || hasBody:
+		parts = append(parts, "-X "+method)
+	}
+	parts = append(parts, shellQuote(target))
+	for _, header := range headers {
+		parts = append(parts, "-H "+shellQuote(header[0]+": "+header[1]))
+	}
+	if hasBody {
+		parts = append(parts, "--data-raw "+shellQuote(body))
+	}
+	return joinShellParts(parts)
+}
+
+func renderHTTPieSnippet(method, target string, headers [][2]string, body string, hasBody bool) string {
+	parts := []string{"http"}
+	if hasBody {
+		parts = append(parts, "--raw "+shellQuote(body))
+	}
+	parts = append(parts, method, shellQuote(target))
+	for _, header := range headers {
+		parts = append(parts, shellQuote(header[0]+":"+header[1]))
+	}
+	return joinShellParts(parts)
+}
+
+func renderGoSnippet(method, target string, headers [][2]string, body string, hasBody bool) (string, error) {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if hasBody {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	bodyExpr := "nil"
+	if hasBody {
+		fmt.Fprintf(&b, "body := strings.NewReader(%s)\n", goStringLiteral(body))
+		bodyExpr = "body"
+	}
+	fmt.Fprintf(&b, "req, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(method), strconv.Quote(target), bodyExpr)
+	b.WriteString("if err != nil {\npanic(err)\n}\n")
+	for _, header := range headers {
+		fmt.Fprintf(&b, "req.Header.Add(%s, %s)\n", strconv.Quote(header[0]), strconv.Quote(header[1]))
+	}
+	b.WriteString("\nresp, err := http.DefaultClient.Do(req)\nif err != nil {\npanic(err)\n}\ndefer resp.Body.Close()\n\n")
+	b.WriteString("data, err := io.ReadAll(resp.Body)\nif err != nil {\npanic(err)\n}\nfmt.Println(resp.Status)\nfmt.Println(string(data))\n}\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// goStringLiteral 多行内容优先使用原始字符串字面量, 便于阅读
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "\n") && !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// joinShellParts 参数较多时每个参数单独一行, 用续行符连接
+func joinShellParts(parts []string) string {
+	if len(parts) <= 3 {
+		return strings.Join(parts, " ")
+	}
+	return strings.Join(parts, " \\\n  ")
+}
+
+// shellQuote 为 POSIX shell 加单引号, 安全的参数保持原样
+func shellQuote(s string) string {
+	if shellSafeWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"tools-ollama/types"
+)
+
+func TestParseCurlCommandLine(t *testing.T) {
+	header := func(key, value string) types.RequestHeader {
+		return types.RequestHeader{Key: key, Value: value, Enabled: true}
+	}
+	query := func(key, value string) types.QueryParam {
+		return types.QueryParam{Key: key, Value: value, Enabled: true}
+	}
+	jsonBody := func(content string) types.RequestBody {
+		return types.RequestBody{Type: types.RequestBodyTypeRaw, RawContent: content, RawContentType: types.RawBodyContentTypeJson}
+	}
+	noBody := types.RequestBody{Type: types.RequestBodyTypeNone}
+
+	tests := []struct {
+		name    string
+		command string
+		url     string
+		method  string
+		query   []types.QueryParam
+		headers []types.RequestHeader
+		body    types.RequestBody
+	}{
+		{
+			name:    "plain get",
+			command: "curl http://localhost:11434/api/tags",
+			url:     "http://localhost:11434/api/tags",
+			method:  "GET",
+			body:    noBody,
+		},
+		{
+			name:    "json body defaults to post",
+			command: "curl http://localhost:11434/api/generate -d '{\n  \"model\": \"llama3.2\"\n}'",
+			url:     "http://localhost:11434/api/generate",
+			method:  "POST",
+			body:    jsonBody("{\n  \"model\": \"llama3.2\"\n}"),
+		},
+		{
+			name:    "dump header to stdout",
+			command: "curl -D - http://host/api/tags",
+			url:     "http://host/api/tags",
+			method:  "GET",
+			body:    noBody,
+		},
+		{
+			name:    "dump header long option",
+			command: "curl --dump-header headers.txt -o out.json http://host/api/tags",
+			url:     "http://host/api/tags",
+			method:  "GET",
+			body:    noBody,
+		},
+		{
+			name:    "value options before url",
+			command: "curl -t TTYPE=vt100 -Q 'NOOP' -y 30 -Y 1000 -z 'Jan 1 2024' -C - -P - -U u:p --aws-sigv4 aws:amz:us-east-1:s3 --connect-to a:80:b:8080 http://host/x",
+			url:     "http://host/x",
+			method:  "GET",
+			body:    noBody,
+		},
+		{
+			name:    "combined short flags with attached value",
+			command: `curl -sSLXPUT "http://host/api/x?a=1&b=%20c" -H 'Content-Type: application/xml' -H"Accept: */*" --data-raw $'<a>it\'s</a>' --compressed`,
+			url:     "http://host/api/x?a=1&b=%20c",
+			method:  "PUT",
+			query:   []types.QueryParam{query("a", "1"), query("b", " c")},
+			headers: []types.RequestHeader{header("Content-Type", "application/xml"), header("Accept", "*/*")},
+			body:    types.RequestBody{Type: types.RequestBodyTypeRaw, RawContent: "<a>it's</a>", RawContentType: types.RawBodyContentTypeXml},
+		},
+		{
+			name:    "basic auth and bearer",
+			command: "curl -u user:pw --oauth2-bearer ignored http://host/",
+			url:     "http://host/",
+			method:  "GET",
+			headers: []types.RequestHeader{header("Authorization", "Basic dXNlcjpwdw==")},
+			body:    noBody,
+		},
+		{
+			name:    "get moves data to query",
+			command: "curl -G example.com/search -d q=a+b --data-urlencode 'x=1 2'",
+			url:     "example.com/search",
+			method:  "GET",
+			query:   []types.QueryParam{query("q", "a b"), query("x", "1 2")},
+			body:    noBody,
+		},
+		{
+			name:    "form fields",
+			command: "curl -F name=x -F 'k=v;type=text/plain' http://host",
+			url:     "http://host",
+			method:  "POST",
+			body:    types.RequestBody{Type: types.RequestBodyTypeFormData, FormData: []types.FormDataItem{{Key: "name", Value: "x"}, {Key: "k", Value: "v"}}},
+		},
+		{
+			name:    "url query options",
+			command: "curl --url-query 'q=a b' --url-query +raw=%2F 'http://host/api/x?a=1'",
+			url:     "http://host/api/x?a=1",
+			method:  "GET",
+			query:   []types.QueryParam{query("a", "1"), query("q", "a b"), query("raw", "/")},
+			body:    noBody,
+		},
+		{
+			name:    "urlencoded data",
+			command: "curl -d a=1 -d b=2 http://host/x",
+			url:     "http://host/x",
+			method:  "POST",
+			body:    types.RequestBody{Type: types.RequestBodyTypeFormData, FormData: []types.FormDataItem{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}},
+		},
+		{
+			name:    "json option sets headers",
+			command: `curl --json '{"a":1}' http://host/api/chat`,
+			url:     "http://host/api/chat",
+			method:  "POST",
+			headers: []types.RequestHeader{header("Content-Type", "application/json"), header("Accept", "application/json")},
+			body:    jsonBody(`{"a":1}`),
+		},
+		{
+			name:    "head request",
+			command: "curl -I --url http://host/api/version",
+			url:     "http://host/api/version",
+			method:  "HEAD",
+			body:    noBody,
+		},
+		{
+			name:    "expand options keep variables",
+			command: "curl --variable host=x --expand-header 'X-Host: {{host}}' http://host/",
+			url:     "http://host/",
+			method:  "GET",
+			headers: []types.RequestHeader{header("X-Host", "{{host}}")},
+			body:    noBody,
+		},
+		{
+			name:    "known and negated flags",
+			command: "curl --location --no-progress-meter --http1.1 http://host/",
+			url:     "http://host/",
+			method:  "GET",
+			body:    noBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := parseCurlCommandLine(tt.command)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if cmd.url != tt.url {
+				t.Errorf("url = %q, want %q", cmd.url, tt.url)
+			}
+			request, err := cmd.toApiRequest()
+			if err != nil {
+				t.Fatalf("toApiRequest: %v", err)
+			}
+			if request.Method != tt.method {
+				t.Errorf("method = %q, want %q", request.Method, tt.method)
+			}
+			if tt.query == nil {
+				tt.query = []types.QueryParam{}
+			}
+			if !reflect.DeepEqual(request.QueryParams, tt.query) {
+				t.Errorf("query = %+v, want %+v", request.QueryParams, tt.query)
+			}
+			if tt.headers == nil {
+				tt.headers = []types.RequestHeader{}
+			}
+			if !reflect.DeepEqual(request.Headers, tt.headers) {
+				t.Errorf("headers = %+v, want %+v", request.Headers, tt.headers)
+			}
+			if !reflect.DeepEqual(request.Body, tt.body) {
+				t.Errorf("body = %+v, want %+v", request.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestParseCurlCommandLineWarnings(t *testing.T) {
+	cmd, err := parseCurlCommandLine("curl -F a=1 -F b=2 http://host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmd.warnings) != 1 || !strings.Contains(cmd.warnings[0], "multipart") {
+		t.Errorf("warnings = %q, want one multipart warning", cmd.warnings)
+	}
+
+	cmd, err = parseCurlCommandLine("curl -d a=1 http://host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmd.warnings) != 0 {
+		t.Errorf("warnings = %q, want none", cmd.warnings)
+	}
+}
+
+func TestParseCurlCommandLineErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"empty", "curl", "curl 命令为空"},
+		{"no url", "curl -X POST", "没有请求地址"},
+		{"missing value", "curl http://host -H", "缺少参数"},
+		{"unterminated quote", "curl 'http://host", ""},
+		{"file form field", "curl -F f=@x.png http://host", "不支持上传文件"},
+		{"data from file", "curl -d @body.json http://host", "不支持从文件读取"},
+		{"url query from file", "curl --url-query q@file.txt http://host", "不支持从文件读取"},
+		{"form and data", "curl -F a=1 -d b=2 http://host", "不能同时使用"},
+		{"unknown option with argument", "curl --some-new-option value http://host", "无法识别的 curl 选项 --some-new-option"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := parseCurlCommandLine(tt.command)
+			if err == nil {
+				_, err = cmd.toApiRequest()
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{`curl -H 'A: b' "x y"`, []string{"curl", "-H", "A: b", "x y"}},
+		{"curl \\\n  http://host", []string{"curl", "http://host"}},
+		{`curl -d "a\"b\$c"`, []string{"curl", "-d", `a"b$c`}},
+		{`curl $'a\nb\'c'`, []string{"curl", "a\nb'c"}},
+		{`curl a''b ""`, []string{"curl", "ab", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitShellWords(tt.command)
+		if err != nil {
+			t.Errorf("splitShellWords(%q): %v", tt.command, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShellWords(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 	"tools-ollama/types"
 
+	"github.com/16chusi/duolasdk"
 	"github.com/16chusi/duolasdk/core"
 )
 
@@ -18,13 +20,20 @@ type OllamaApiDebugger struct {
 	ctx       context.Context
 	logger    *core.AppLog
 	configMgr *OllamaConfigManager
+	store     *duolasdk.AppStore
+	historyMu sync.Mutex // 保护历史记录的写入和清理
+
+	streamsMu sync.Mutex
+	streams   map[string]context.CancelFunc // 进行中的流式请求, 用于取消
 }
 
 // NewOllamaApiDebugger 创建一个新的 OllamaApiDebugger 实例
-func NewOllamaApiDebugger(logger *core.AppLog, configMgr *OllamaConfigManager) *OllamaApiDebugger {
+func NewOllamaApiDebugger(logger *core.AppLog, configMgr *OllamaConfigManager, store *duolasdk.AppStore) *OllamaApiDebugger {
 	return &OllamaApiDebugger{
 		logger:    logger.WithPrefix("ApiDebugger"),
 		configMgr: configMgr,
+		store:     store,
+		streams:   make(map[string]context.CancelFunc),
 	}
 }
 
@@ -33,26 +42,68 @@ func (d *OllamaApiDebugger) SetContext(ctx context.Context) {
 	d.ctx = ctx
 }
 
-// SendHttpRequest 使用 Go 标准库 net/http 直接处理前端发送的 HTTP 请求
+// SendHttpRequest 使用 Go 标准库 net/http 直接处理前端发送的 HTTP 请求, 并记录到历史中
 func (d *OllamaApiDebugger) SendHttpRequest(request types.ApiRequest) (types.ApiResponse, error) {
+	apiResponse, finalURL := d.sendHttpRequest(request)
+	d.recordHistory(request, apiResponse, finalURL, "")
+	return apiResponse, nil
+}
+
+// sendHttpRequest 发送请求, 返回响应和最终请求的 URL; 失败信息写在响应的 Error 字段中
+func (d *OllamaApiDebugger) sendHttpRequest(request types.ApiRequest) (types.ApiResponse, string) {
 	d.logger.Debug("Received API Debugger Request", "method", request.Method, "path", request.Path, "serverID", request.SelectedServerID)
+	request = d.resolveApiVariables(request)
 
 	var apiResponse types.ApiResponse
 	startTime := time.Now()
 
+	req, finalURL, err := d.buildHttpRequest(d.ctx, request)
+	if err != nil {
+		d.logger.Errorf("Failed to build HTTP request: %v", err)
+		apiResponse.Error = err.Error()
+		return apiResponse, finalURL
+	}
+
+	// 1. 发送请求
+	d.logger.Debug("Sending HTTP request via stdlib", "method", req.Method, "url", req.URL, "headers", req.Header)
+	client := CreateHTTPClientWithTimeout(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		d.logger.Errorf("Failed to send HTTP request: %v", err)
+		apiResponse.Error = fmt.Sprintf("发送请求失败: %v", err)
+		return apiResponse, finalURL
+	}
+	defer resp.Body.Close()
+
+	// 2. 读取并处理响应
+	respBody, err := ReadResponseBody(resp)
+	if err != nil {
+		d.logger.Errorf("Failed to read response body: %v", err)
+		apiResponse.Error = fmt.Sprintf("读取响应失败: %v", err)
+		return apiResponse, finalURL
+	}
+
+	apiResponse.StatusCode = resp.StatusCode
+	apiResponse.StatusText = http.StatusText(resp.StatusCode)
+	apiResponse.Body = respBody
+
+	apiResponse.Headers = responseHeaders(resp.Header)
+
+	apiResponse.RequestDurationMs = time.Since(startTime).Milliseconds()
+	d.logger.Debug("API Debugger Request completed", "statusCode", apiResponse.StatusCode, "duration", apiResponse.RequestDurationMs)
+
+	return apiResponse, finalURL
+}
+
+// buildHttpRequest 根据调试请求构建 http.Request, 同时返回最终请求的 URL; 错误信息可以直接展示给用户
+func (d *OllamaApiDebugger) buildHttpRequest(ctx context.Context, request types.ApiRequest) (*http.Request, string, error) {
 	// 1. 获取 Base URL
-	var baseURL string
-	if request.SelectedServerID != "" {
-		serverConfig, err := d.configMgr.GetServerByID(request.SelectedServerID)
-		if err != nil {
-			d.logger.Errorf("Failed to get server config for ID %s: %v", request.SelectedServerID, err)
-			apiResponse.Error = fmt.Sprintf("无法获取服务器配置: %v", err)
-			return apiResponse, nil
-		}
-		baseURL = serverConfig.BaseURL
-	} else {
-		apiResponse.Error = "未选择Ollama服务或服务配置无效"
-		return apiResponse, nil
+	if request.SelectedServerID == "" {
+		return nil, "", fmt.Errorf("未选择Ollama服务或服务配置无效")
+	}
+	serverConfig, err := d.configMgr.GetServerByID(request.SelectedServerID)
+	if err != nil {
+		return nil, "", fmt.Errorf("无法获取服务器配置: %w", err)
 	}
 
 	// 2. 构建完整的 URL (BaseURL + Path + Query Params)
@@ -62,39 +113,22 @@ func (d *OllamaApiDebugger) SendHttpRequest(request types.ApiRequest) (types.Api
 			queryParams[param.Key] = param.Value
 		}
 	}
-
-	finalURL, err := BuildURLWithQuery(baseURL, request.Path, queryParams)
+	finalURL, err := BuildURLWithQuery(serverConfig.BaseURL, request.Path, queryParams)
 	if err != nil {
-		d.logger.Errorf("Failed to build URL: %v", err)
-		apiResponse.Error = fmt.Sprintf("URL构建失败: %v", err)
-		return apiResponse, nil
+		return nil, "", fmt.Errorf("URL构建失败: %w", err)
 	}
 
 	// 3. 准备请求体 (io.Reader)
 	var bodyReader io.Reader
-	var contentType string
-
-	switch request.Body.Type {
-	case types.RequestBodyTypeRaw:
-		bodyReader = strings.NewReader(request.Body.RawContent)
-		contentType = string(request.Body.RawContentType)
-	case types.RequestBodyTypeFormData:
-		formData := url.Values{}
-		for _, field := range request.Body.FormData {
-			formData.Set(field.Key, field.Value)
-		}
-		bodyReader = strings.NewReader(formData.Encode())
-		contentType = "application/x-www-form-urlencoded"
-	case types.RequestBodyTypeNone:
-		bodyReader = nil
+	content, contentType, hasBody := encodeRequestBody(request.Body)
+	if hasBody {
+		bodyReader = strings.NewReader(content)
 	}
 
 	// 4. 使用 net/http 创建请求
-	req, err := http.NewRequestWithContext(d.ctx, strings.ToUpper(request.Method), finalURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(request.Method), finalURL, bodyReader)
 	if err != nil {
-		d.logger.Errorf("Failed to create HTTP request: %v", err)
-		apiResponse.Error = fmt.Sprintf("创建请求失败: %v", err)
-		return apiResponse, nil
+		return nil, finalURL, fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	// 5. 手动设置所有请求头
@@ -110,38 +144,32 @@ func (d *OllamaApiDebugger) SendHttpRequest(request types.ApiRequest) (types.Api
 			}
 		}
 	}
+	return req, finalURL, nil
+}
 
-	// 6. 发送请求
-	d.logger.Debug("Sending HTTP request via stdlib", "method", req.Method, "url", req.URL, "headers", req.Header)
-	client := CreateHTTPClientWithTimeout(30 * time.Second)
-	resp, err := client.Do(req)
-	if err != nil {
-		d.logger.Errorf("Failed to send HTTP request: %v", err)
-		apiResponse.Error = fmt.Sprintf("发送请求失败: %v", err)
-		return apiResponse, nil
+// responseHeaders 把响应头转换为前端使用的键值列表, 同名的多个值用逗号连接
+func responseHeaders(header http.Header) []types.RequestHeader {
+	var headers []types.RequestHeader
+	for k, v := range header {
+		headers = append(headers, types.RequestHeader{Key: k, Value: strings.Join(v, ", "), Enabled: true})
 	}
-	defer resp.Body.Close()
-
-	// 7. 读取并处理响应
-	respBody, err := ReadResponseBody(resp)
-	if err != nil {
-		d.logger.Errorf("Failed to read response body: %v", err)
-		apiResponse.Error = fmt.Sprintf("读取响应失败: %v", err)
-		return apiResponse, nil
-	}
-
-	apiResponse.StatusCode = resp.StatusCode
-	apiResponse.StatusText = http.StatusText(resp.StatusCode)
-	apiResponse.Body = respBody
+	return headers
+}
 
-	for k, v := range resp.Header {
-		apiResponse.Headers = append(apiResponse.Headers, types.RequestHeader{Key: k, Value: strings.Join(v, ", "), Enabled: true})
+// encodeRequestBody 按请求体类型编码请求体, 返回内容、默认的 Content-Type 以及是否有请求体
+func encodeRequestBody(body types.RequestBody) (string, string, bool) {
+	switch body.Type {
+	case types.RequestBodyTypeRaw:
+		return body.RawContent, string(body.RawContentType), true
+	case types.RequestBodyTypeFormData:
+		formData := url.Values{}
+		for _, field := range body.FormData {
+			formData.Set(field.Key, field.Value)
+		}
+		return formData.Encode(), "application/x-www-form-urlencoded", true
+	default:
+		return "", "", false
 	}
-
-	apiResponse.RequestDurationMs = time.Since(startTime).Milliseconds()
-	d.logger.Debug("API Debugger Request completed", "statusCode", apiResponse.StatusCode, "duration", apiResponse.RequestDurationMs)
-
-	return apiResponse, nil
 }
 
 // GetOllamaServers 用于前端获取Ollama服务器配置列表
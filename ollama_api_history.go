@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+)
+
+const (
+	// apiHistoryKey API 调试器历史记录的哈希键, 字段为记录ID
+	apiHistoryKey = "api_history"
+	// maxApiHistoryEntries 最多保留的历史记录数量, 超出时删除最旧的记录
+	maxApiHistoryEntries = 200
+	// maxApiHistoryBodySize 历史记录中保存的响应体上限, 避免大响应撑大存储
+	maxApiHistoryBodySize = 256 << 10
+)
+
+// ListApiHistory 获取请求历史, 最新的在前; limit <= 0 时返回全部
+func (d *OllamaApiDebugger) ListApiHistory(limit int) ([]types.ApiHistoryEntry, error) {
+	entries, err := d.loadApiHistory()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// GetApiHistoryEntry 获取一条历史记录
+func (d *OllamaApiDebugger) GetApiHistoryEntry(id string) (types.ApiHistoryEntry, error) {
+	data, err := d.store.HGet(apiHistoryKey, id)
+	if err != nil || data == "" {
+		return types.ApiHistoryEntry{}, fmt.Errorf("历史记录不存在: %s", id)
+	}
+	var entry types.ApiHistoryEntry
+	if err := UnmarshalJSONWithError([]byte(data), &entry, d.logger, "解析请求历史"); err != nil {
+		return types.ApiHistoryEntry{}, err
+	}
+	return entry, nil
+}
+
+// DeleteApiHistoryEntry 删除一条历史记录
+func (d *OllamaApiDebugger) DeleteApiHistoryEntry(id string) error {
+	if err := d.store.HDel(apiHistoryKey, id); err != nil {
+		d.logger.Error("删除请求历史失败", "id", id, "error", err)
+		return fmt.Errorf("删除请求历史失败: %w", err)
+	}
+	return nil
+}
+
+// ClearApiHistory 清空请求历史
+func (d *OllamaApiDebugger) ClearApiHistory() error {
+	d.historyMu.Lock()
+	defer d.historyMu.Unlock()
+
+	dataMap, err := d.store.HGetAll(apiHistoryKey)
+	if err != nil {
+		d.logger.Error("获取请求历史失败", "error", err)
+		return fmt.Errorf("获取请求历史失败: %w", err)
+	}
+	for id := range dataMap {
+		if err := d.store.HDel(apiHistoryKey, id); err != nil {
+			d.logger.Error("删除请求历史失败", "id", id, "error", err)
+			return fmt.Errorf("删除请求历史失败: %w", err)
+		}
+	}
+	d.logger.Info("请求历史已清空", "count", len(dataMap))
+	return nil
+}
+
+// ReplayApiHistory 重新发送历史记录中的请求, serverID 不为空时发送到指定的服务器
+// 重放的结果同样记录到历史中
+func (d *OllamaApiDebugger) ReplayApiHistory(id string, serverID string) (types.ApiResponse, error) {
+	entry, err := d.GetApiHistoryEntry(id)
+	if err != nil {
+		return types.ApiResponse{}, err
+	}
+	request := entry.Request
+	if serverID != "" {
+		request.SelectedServerID = serverID
+	}
+	d.logger.Debug("重放请求历史", "id", id, "serverID", request.SelectedServerID)
+
+	response, finalURL := d.sendHttpRequest(request)
+	d.recordHistory(request, response, finalURL, entry.ID)
+	return response, nil
+}
+
+// recordHistory 记录一次已发送的请求, 失败只记录日志
+func (d *OllamaApiDebugger) recordHistory(request types.ApiRequest, response types.ApiResponse, finalURL string, replayOf string) {
+	if d.store == nil {
+		return
+	}
+	entry := types.ApiHistoryEntry{
+		ID:        GenerateUniqueID(),
+		Request:   request,
+		Response:  response,
+		URL:       finalURL,
+		ReplayOf:  replayOf,
+		Timestamp: GetCurrentTimestamp(),
+	}
+	if server, err := d.configMgr.GetServerByID(request.SelectedServerID); err == nil {
+		entry.ServerName = server.Name
+	}
+	if len(entry.Response.Body) > maxApiHistoryBodySize {
+		entry.Response.Body = strings.ToValidUTF8(entry.Response.Body[:maxApiHistoryBodySize], "")
+		entry.Truncated = true
+	}
+
+	data, err := MarshalJSONWithError(entry, d.logger, "序列化请求历史")
+	if err != nil {
+		return
+	}
+
+	d.historyMu.Lock()
+	defer d.historyMu.Unlock()
+	if err := d.store.HSet(apiHistoryKey, entry.ID, string(data)); err != nil {
+		d.logger.Warn("保存请求历史失败", "error", err)
+		return
+	}
+	d.pruneApiHistory()
+}
+
+// pruneApiHistory 删除超出数量上限的最旧记录, 调用方需持有 historyMu
+func (d *OllamaApiDebugger) pruneApiHistory() {
+	entries, err := d.loadApiHistory()
+	if err != nil || len(entries) <= maxApiHistoryEntries {
+		return
+	}
+	for _, entry := range entries[maxApiHistoryEntries:] {
+		if err := d.store.HDel(apiHistoryKey, entry.ID); err != nil {
+			d.logger.Warn("清理请求历史失败", "id", entry.ID, "error", err)
+		}
+	}
+}
+
+// loadApiHistory 读取全部历史记录, 按时间倒序排列
+func (d *OllamaApiDebugger) loadApiHistory() ([]types.ApiHistoryEntry, error) {
+	dataMap, err := d.store.HGetAll(apiHistoryKey)
+	if err != nil {
+		d.logger.Error("获取请求历史失败", "error", err)
+		return nil, fmt.Errorf("获取请求历史失败: %w", err)
+	}
+	entries := make([]types.ApiHistoryEntry, 0, len(dataMap))
+	for _, data := range dataMap {
+		var entry types.ApiHistoryEntry
+		if err := UnmarshalJSONWithError([]byte(data), &entry, d.logger, "解析请求历史"); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Timestamp != entries[j].Timestamp {
+			return entries[i].Timestamp > entries[j].Timestamp
+		}
+		return entries[i].ID > entries[j].ID
+	})
+	return entries, nil
+}
@@ -0,0 +1,752 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// postmanSchemaV21 导出时使用的 Postman 集合格式
+const postmanSchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Postman v2.0 / v2.1 集合格式, 只保留调试器能表达的字段
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	PostmanID   string             `json:"_postman_id,omitempty"`
+	Name        string             `json:"name"`
+	Description postmanDescription `json:"description,omitempty"`
+	Schema      string             `json:"schema"`
+}
+
+// postmanItem 既可以是文件夹 (包含 Item), 也可以是请求 (包含 Request)
+type postmanItem struct {
+	Name        string             `json:"name"`
+	Description postmanDescription `json:"description,omitempty"`
+	Item        []postmanItem      `json:"item,omitempty"`
+	Request     *postmanRequest    `json:"request,omitempty"`
+	Event       json.RawMessage    `json:"event,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string             `json:"method"`
+	Header      []postmanKeyValue  `json:"header"`
+	Body        *postmanBody       `json:"body,omitempty"`
+	URL         postmanURL         `json:"url"`
+	Auth        *postmanAuth       `json:"auth,omitempty"`
+	Description postmanDescription `json:"description,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"` // formdata 中的 text / file
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type postmanVariable struct {
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"`
+	Disabled bool            `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string              `json:"mode"`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue   `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue   `json:"formdata,omitempty"`
+	Options    *postmanBodyOptions `json:"options,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     postmanStrings    `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     postmanStrings    `json:"path,omitempty"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+}
+
+// postmanDescription 兼容字符串和 {"content": "..."} 两种写法
+type postmanDescription string
+
+// postmanStrings 兼容字符串、字符串数组以及 {"value": "..."} 形式的路径段
+type postmanStrings []string
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (d *postmanDescription) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*d = postmanDescription(text)
+		return nil
+	}
+	var object struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*d = postmanDescription(object.Content)
+	return nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (s *postmanStrings) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*s = postmanStrings{text}
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	values := make(postmanStrings, 0, len(items))
+	for _, item := range items {
+		var value string
+		if err := json.Unmarshal(item, &value); err != nil {
+			var object struct {
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal(item, &object); err != nil {
+				return err
+			}
+			value = object.Value
+		}
+		values = append(values, value)
+	}
+	*s = values
+	return nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler, url 可以直接是字符串
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler, request 可以直接是 URL 字符串
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*r = postmanRequest{Method: "GET", URL: postmanURL{Raw: raw}}
+		return nil
+	}
+	type plain postmanRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// String 返回完整的原始 URL, 没有 raw 字段时由各部分拼接
+func (u postmanURL) String() string {
+	if strings.TrimSpace(u.Raw) != "" {
+		return strings.TrimSpace(u.Raw)
+	}
+	var b strings.Builder
+	if u.Protocol != "" {
+		b.WriteString(u.Protocol + "://")
+	}
+	b.WriteString(strings.Join(u.Host, "."))
+	if u.Port != "" {
+		b.WriteString(":" + u.Port)
+	}
+	if len(u.Path) > 0 {
+		b.WriteString("/" + strings.Join(u.Path, "/"))
+	}
+	return b.String()
+}
+
+// SelectPostmanCollectionFile 弹出文件选择对话框, 返回选中的 Postman 集合文件
+func (d *OllamaApiDebugger) SelectPostmanCollectionFile() (string, error) {
+	path, err := runtime.OpenFileDialog(d.ctx, runtime.OpenDialogOptions{
+		Title: "选择要导入的 Postman 集合",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Postman Collection", Pattern: "*.json"},
+		},
+	})
+	if err != nil {
+		d.logger.Error("打开文件对话框失败", "error", err)
+		return "", fmt.Errorf("打开文件对话框失败: %w", err)
+	}
+	return path, nil
+}
+
+// ImportPostmanCollectionFromFile 从文件导入 Postman 集合
+func (d *OllamaApiDebugger) ImportPostmanCollectionFromFile(path string) (types.PostmanImportReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.logger.Error("读取 Postman 集合文件失败", "path", path, "error", err)
+		return types.PostmanImportReport{}, fmt.Errorf("读取 Postman 集合文件失败: %w", err)
+	}
+	return d.ImportPostmanCollection(string(data))
+}
+
+// ImportPostmanCollection 把 Postman v2.0 / v2.1 集合导入为新的请求集合
+// 文件夹映射为文件夹路径, 集合变量保留为 {{变量}}, 请求的基础地址映射到已有的服务器配置, 没有匹配时保持未关联并记录在报告中
+func (d *OllamaApiDebugger) ImportPostmanCollection(data string) (types.PostmanImportReport, error) {
+	var source postmanCollection
+	if err := json.Unmarshal([]byte(data), &source); err != nil {
+		d.logger.Error("解析 Postman 集合失败", "error", err)
+		return types.PostmanImportReport{}, fmt.Errorf("解析 Postman 集合失败: %w", err)
+	}
+	if strings.Contains(source.Info.Schema, "/v1.") {
+		return types.PostmanImportReport{}, fmt.Errorf("不支持 Postman v1 格式的集合, 请在 Postman 中导出为 v2.1 格式")
+	}
+	if source.Info.Name == "" && len(source.Item) == 0 {
+		return types.PostmanImportReport{}, fmt.Errorf("不是有效的 Postman 集合")
+	}
+
+	servers, err := d.configMgr.GetServers()
+	if err != nil {
+		return types.PostmanImportReport{}, err
+	}
+	imp := &postmanImporter{
+		servers:   servers,
+		serverIDs: make(map[string]string),
+		variables: make(map[string]string),
+		report:    types.PostmanImportReport{UnmatchedBaseURLs: []string{}, UnassignedRequests: []string{}, Warnings: []string{}},
+	}
+
+	collection := types.ApiCollection{
+		Name:        strings.TrimSpace(source.Info.Name),
+		Description: string(source.Info.Description),
+	}
+	if collection.Name == "" {
+		collection.Name = "Postman 导入"
+	}
+	for _, variable := range source.Variable {
+		if variable.Disabled || strings.TrimSpace(variable.Key) == "" {
+			continue
+		}
+		value := postmanVariableValue(variable.Value)
+		imp.variables[variable.Key] = value
+		collection.Variables = append(collection.Variables, types.ApiVariable{Key: variable.Key, Value: value})
+	}
+
+	imp.importItems(&collection, source.Item, "", source.Auth)
+
+	saved, err := d.SaveApiCollection(collection)
+	if err != nil {
+		return types.PostmanImportReport{}, err
+	}
+	imp.report.Collection = saved
+	imp.report.Imported = len(saved.Requests)
+	d.logger.Info("Postman 集合已导入", "name", saved.Name, "requests", imp.report.Imported, "unmatchedBaseUrls", len(imp.report.UnmatchedBaseURLs), "warnings", len(imp.report.Warnings))
+	return imp.report, nil
+}
+
+// postmanImporter 保存一次导入过程中的变量、服务器映射和导入报告
+type postmanImporter struct {
+	servers   []types.OllamaServerConfig
+	serverIDs map[string]string // 规范化的基础地址 -> 服务器ID
+	variables map[string]string
+	report    types.PostmanImportReport
+}
+
+func (imp *postmanImporter) warn(format string, args ...interface{}) {
+	imp.report.Warnings = append(imp.report.Warnings, fmt.Sprintf(format, args...))
+}
+
+// importItems 递归转换文件夹和请求, auth 为从上级继承的认证配置
+func (imp *postmanImporter) importItems(collection *types.ApiCollection, items []postmanItem, folder string, auth *postmanAuth) {
+	for _, item := range items {
+		if item.Request == nil {
+			path := normalizeApiFolder(folder + "/" + strings.ReplaceAll(item.Name, "/", "-"))
+			if path == "" {
+				path = normalizeApiFolder(folder + "/未命名文件夹")
+			}
+			collection.Folders = append(collection.Folders, path)
+			imp.importItems(collection, item.Item, path, auth)
+			continue
+		}
+		if len(item.Event) > 0 && string(item.Event) != "null" && string(item.Event) != "[]" {
+			imp.warn("请求 %q 的脚本未导入", item.Name)
+		}
+		collection.Requests = append(collection.Requests, imp.convertRequest(item, folder, auth))
+	}
+}
+
+// convertRequest 把 Postman 请求转换为调试器请求
+func (imp *postmanImporter) convertRequest(item postmanItem, folder string, auth *postmanAuth) types.ApiSavedRequest {
+	source := item.Request
+	saved := types.ApiSavedRequest{
+		Name:        strings.TrimSpace(item.Name),
+		Description: string(source.Description),
+		Folder:      folder,
+	}
+	if saved.Name == "" {
+		saved.Name = "未命名请求"
+	}
+	if saved.Description == "" {
+		saved.Description = string(item.Description)
+	}
+
+	request := types.ApiRequest{
+		Method:      strings.ToUpper(strings.TrimSpace(source.Method)),
+		QueryParams: []types.QueryParam{},
+		Headers:     []types.RequestHeader{},
+		Body:        types.RequestBody{Type: types.RequestBodyTypeNone},
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	base, path, rawQuery := splitPostmanURL(source.URL.String())
+	request.Path = path
+	request.SelectedServerID = imp.resolveServer(base, saved.Name)
+	if request.SelectedServerID == "" {
+		imp.report.UnassignedRequests = append(imp.report.UnassignedRequests, saved.Name)
+	}
+	if len(source.URL.Query) > 0 {
+		for _, param := range source.URL.Query {
+			request.QueryParams = append(request.QueryParams, types.QueryParam{Key: param.Key, Value: param.Value, Enabled: !param.Disabled})
+		}
+	} else {
+		request.QueryParams = append(request.QueryParams, parseRawQueryParams(rawQuery)...)
+	}
+
+	for _, header := range source.Header {
+		request.Headers = append(request.Headers, types.RequestHeader{Key: header.Key, Value: header.Value, Enabled: !header.Disabled})
+	}
+	if source.Auth != nil && source.Auth.Type != "inherit" {
+		auth = source.Auth
+	}
+	imp.applyAuth(&request, auth, saved.Name)
+	request.Body = imp.convertBody(source.Body, request.Headers, saved.Name)
+
+	saved.Request = request
+	return saved
+}
+
+// applyAuth 把 Bearer 认证转换为 Authorization 请求头, 其他认证方式只记录警告
+func (imp *postmanImporter) applyAuth(request *types.ApiRequest, auth *postmanAuth, name string) {
+	if auth == nil || auth.Type == "" || auth.Type == "noauth" {
+		return
+	}
+	if auth.Type != "bearer" {
+		imp.warn("请求 %q 的 %s 认证未导入", name, auth.Type)
+		return
+	}
+	for _, header := range request.Headers {
+		if strings.EqualFold(header.Key, "Authorization") {
+			return
+		}
+	}
+	for _, field := range auth.Bearer {
+		if field.Key == "token" {
+			request.Headers = append(request.Headers, types.RequestHeader{Key: "Authorization", Value: "Bearer " + field.Value, Enabled: true})
+			return
+		}
+	}
+}
+
+// convertBody 转换请求体, raw 的内容类型优先取 options.raw.language, 其次取 Content-Type 请求头
+func (imp *postmanImporter) convertBody(body *postmanBody, headers []types.RequestHeader, name string) types.RequestBody {
+	if body == nil || body.Mode == "" {
+		return types.RequestBody{Type: types.RequestBodyTypeNone}
+	}
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return types.RequestBody{Type: types.RequestBodyTypeNone}
+		}
+		language := ""
+		if body.Options != nil {
+			language = body.Options.Raw.Language
+		}
+		return types.RequestBody{
+			Type:           types.RequestBodyTypeRaw,
+			RawContent:     body.Raw,
+			RawContentType: postmanRawContentType(language, headers, body.Raw),
+		}
+	case "urlencoded", "formdata":
+		fields := body.URLEncoded
+		if body.Mode == "formdata" {
+			fields = body.FormData
+		}
+		items := make([]types.FormDataItem, 0, len(fields))
+		for _, field := range fields {
+			if field.Disabled {
+				continue
+			}
+			if field.Type == "file" {
+				imp.warn("请求 %q 的文件字段 %q 未导入", name, field.Key)
+				continue
+			}
+			items = append(items, types.FormDataItem{Key: field.Key, Value: field.Value})
+		}
+		return types.RequestBody{Type: types.RequestBodyTypeFormData, FormData: items}
+	default:
+		imp.warn("请求 %q 的 %s 请求体未导入", name, body.Mode)
+		return types.RequestBody{Type: types.RequestBodyTypeNone}
+	}
+}
+
+// resolveServer 把基础地址映射到已有的服务器配置, 基础地址中的变量先用集合变量替换
+// 不会自动创建服务器: 没有匹配的服务器时请求保持未关联, 基础地址记录在导入报告中
+func (imp *postmanImporter) resolveServer(base string, name string) string {
+	resolved := strings.TrimSpace(replaceApiVariables(base, imp.variables))
+	if resolved == "" || strings.Contains(resolved, "{{") {
+		imp.warn("请求 %q 的基础地址 %q 无法解析, 未关联服务器", name, base)
+		return ""
+	}
+	if !strings.Contains(resolved, "://") {
+		resolved = "http://" + resolved
+	}
+	resolved = strings.TrimRight(resolved, "/")
+	key := strings.ToLower(resolved)
+	if id, ok := imp.serverIDs[key]; ok {
+		return id
+	}
+
+	for _, server := range imp.servers {
+		if strings.ToLower(strings.TrimRight(server.BaseURL, "/")) == key {
+			imp.serverIDs[key] = server.ID
+			return server.ID
+		}
+	}
+	imp.serverIDs[key] = ""
+	imp.report.UnmatchedBaseURLs = append(imp.report.UnmatchedBaseURLs, resolved)
+	return ""
+}
+
+// splitPostmanURL 把原始 URL 拆分为基础地址、路径和查询字符串
+// 基础地址可以是 {{baseUrl}} 形式的变量, 也可以是 scheme://host[:port]
+func splitPostmanURL(raw string) (base, path, query string) {
+	raw, _, _ = strings.Cut(raw, "#")
+	raw, query, _ = strings.Cut(raw, "?")
+
+	switch {
+	case strings.HasPrefix(raw, "{{"):
+		if end := strings.Index(raw, "}}"); end >= 0 {
+			base, path = raw[:end+2], raw[end+2:]
+		} else {
+			path = raw
+		}
+	case strings.Contains(raw, "://"):
+		scheme, rest, _ := strings.Cut(raw, "://")
+		host, rest, found := strings.Cut(rest, "/")
+		base = scheme + "://" + host
+		if found {
+			path = "/" + rest
+		}
+	case strings.HasPrefix(raw, "/"):
+		path = raw
+	default:
+		host, rest, found := strings.Cut(raw, "/")
+		base = host
+		if found {
+			path = "/" + rest
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return base, path, query
+}
+
+// parseRawQueryParams 按原始顺序解析查询字符串, 键和值都会做 URL 解码
+func parseRawQueryParams(rawQuery string) []types.QueryParam {
+	params := []types.QueryParam{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		params = append(params, types.QueryParam{Key: key, Value: value, Enabled: true})
+	}
+	return params
+}
+
+func postmanRawContentType(language string, headers []types.RequestHeader, content string) types.RawBodyContentType {
+	switch strings.ToLower(language) {
+	case "json":
+		return types.RawBodyContentTypeJson
+	case "html":
+		return types.RawBodyContentTypeHtml
+	case "xml":
+		return types.RawBodyContentTypeXml
+	case "text", "javascript":
+		return types.RawBodyContentTypeText
+	}
+	for _, header := range headers {
+		if !header.Enabled || !strings.EqualFold(header.Key, "Content-Type") {
+			continue
+		}
+		for _, contentType := range []types.RawBodyContentType{types.RawBodyContentTypeJson, types.RawBodyContentTypeHtml, types.RawBodyContentTypeXml, types.RawBodyContentTypeText} {
+			if strings.HasPrefix(strings.ToLower(header.Value), string(contentType)) {
+				return contentType
+			}
+		}
+	}
+	if trimmed := strings.TrimSpace(content); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return types.RawBodyContentTypeJson
+	}
+	return types.RawBodyContentTypeText
+}
+
+// postmanVariableValue 变量值可能是数字或布尔值, 非字符串时保留 JSON 文本
+func postmanVariableValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
+
+// ExportPostmanCollection 把请求集合导出为 Postman v2.1 格式的 JSON
+// 服务器的基础地址导出为集合变量, 已有变量的值与基础地址相同时直接复用该变量
+func (d *OllamaApiDebugger) ExportPostmanCollection(collectionID string) (string, error) {
+	collection, err := d.GetApiCollection(collectionID)
+	if err != nil {
+		return "", err
+	}
+	data, err := d.renderPostmanCollection(collection)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ExportPostmanCollectionToFile 弹出保存对话框, 把请求集合导出为 Postman 集合文件
+func (d *OllamaApiDebugger) ExportPostmanCollectionToFile(collectionID string) (string, error) {
+	collection, err := d.GetApiCollection(collectionID)
+	if err != nil {
+		return "", err
+	}
+	data, err := d.renderPostmanCollection(collection)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := runtime.SaveFileDialog(d.ctx, runtime.SaveDialogOptions{
+		Title:           "导出 Postman 集合",
+		DefaultFilename: sanitizeFileName(collection.Name) + ".postman_collection.json",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Postman Collection", Pattern: "*.json"},
+		},
+	})
+	if err != nil {
+		d.logger.Error("打开保存对话框失败", "error", err)
+		return "", fmt.Errorf("打开保存对话框失败: %w", err)
+	}
+	if path == "" {
+		return "", nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		d.logger.Error("写入导出文件失败", "path", path, "error", err)
+		return "", fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	d.logger.Info("Postman 集合已导出", "path", path, "requests", len(collection.Requests))
+	return path, nil
+}
+
+func (d *OllamaApiDebugger) renderPostmanCollection(collection types.ApiCollection) ([]byte, error) {
+	output := postmanCollection{
+		Info: postmanInfo{
+			PostmanID:   collection.ID,
+			Name:        collection.Name,
+			Description: postmanDescription(collection.Description),
+			Schema:      postmanSchemaV21,
+		},
+	}
+	for _, variable := range collection.Variables {
+		value, _ := json.Marshal(variable.Value)
+		output.Variable = append(output.Variable, postmanVariable{Key: variable.Key, Value: value})
+	}
+
+	bases := make(map[string]string) // 服务器ID -> 导出的基础地址
+	baseFor := func(serverID string) string {
+		if base, ok := bases[serverID]; ok {
+			return base
+		}
+		server, err := d.configMgr.GetServerByID(serverID)
+		if serverID == "" || err != nil {
+			bases[serverID] = ""
+			return ""
+		}
+		baseURL := strings.TrimRight(server.BaseURL, "/")
+		for _, variable := range output.Variable {
+			if strings.TrimRight(postmanVariableValue(variable.Value), "/") == baseURL {
+				bases[serverID] = "{{" + variable.Key + "}}"
+				return bases[serverID]
+			}
+		}
+		key := uniquePostmanVariable(output.Variable, "baseUrl")
+		value, _ := json.Marshal(baseURL)
+		output.Variable = append(output.Variable, postmanVariable{Key: key, Value: value})
+		bases[serverID] = "{{" + key + "}}"
+		return bases[serverID]
+	}
+
+	requests := make(map[string][]postmanItem)
+	for _, saved := range collection.Requests {
+		folder := normalizeApiFolder(saved.Folder)
+		requests[folder] = append(requests[folder], postmanRequestItem(saved, baseFor(saved.Request.SelectedServerID)))
+	}
+	folders := append([]string(nil), collection.Folders...)
+	for folder := range requests {
+		folders = append(folders, folder)
+	}
+	output.Item = buildPostmanItems("", folders, requests)
+	if output.Item == nil {
+		output.Item = []postmanItem{}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(output); err != nil {
+		d.logger.Error("序列化 Postman 集合失败", "error", err)
+		return nil, fmt.Errorf("序列化 Postman 集合失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// buildPostmanItems 生成 parent 下的子文件夹和请求, 文件夹在前并按名称排序
+func buildPostmanItems(parent string, folders []string, requests map[string][]postmanItem) []postmanItem {
+	children := make(map[string]bool)
+	for _, folder := range folders {
+		if folder == "" || folder == parent || (parent != "" && !inApiFolder(folder, parent)) {
+			continue
+		}
+		rest := folder
+		if parent != "" {
+			rest = strings.TrimPrefix(folder, parent+"/")
+		}
+		name, _, _ := strings.Cut(rest, "/")
+		children[name] = true
+	}
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var items []postmanItem
+	for _, name := range names {
+		path := name
+		if parent != "" {
+			path = parent + "/" + name
+		}
+		items = append(items, postmanItem{Name: name, Item: buildPostmanItems(path, folders, requests)})
+	}
+	return append(items, requests[parent]...)
+}
+
+func postmanRequestItem(saved types.ApiSavedRequest, base string) postmanItem {
+	request := saved.Request
+	path := request.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	target := postmanURL{Raw: base + path}
+	if base != "" {
+		target.Host = postmanStrings{base}
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			target.Path = append(target.Path, segment)
+		}
+	}
+	var enabled []string
+	for _, param := range request.QueryParams {
+		target.Query = append(target.Query, postmanKeyValue{Key: param.Key, Value: param.Value, Disabled: !param.Enabled})
+		if param.Enabled {
+			enabled = append(enabled, param.Key+"="+param.Value)
+		}
+	}
+	if len(enabled) > 0 {
+		target.Raw += "?" + strings.Join(enabled, "&")
+	}
+
+	output := &postmanRequest{
+		Method: strings.ToUpper(request.Method),
+		Header: []postmanKeyValue{},
+		URL:    target,
+	}
+	for _, header := range request.Headers {
+		output.Header = append(output.Header, postmanKeyValue{Key: header.Key, Value: header.Value, Disabled: !header.Enabled})
+	}
+	switch request.Body.Type {
+	case types.RequestBodyTypeRaw:
+		body := &postmanBody{Mode: "raw", Raw: request.Body.RawContent, Options: &postmanBodyOptions{}}
+		body.Options.Raw.Language = postmanRawLanguage(request.Body.RawContentType)
+		output.Body = body
+	case types.RequestBodyTypeFormData:
+		body := &postmanBody{Mode: "urlencoded", URLEncoded: []postmanKeyValue{}}
+		for _, field := range request.Body.FormData {
+			body.URLEncoded = append(body.URLEncoded, postmanKeyValue{Key: field.Key, Value: field.Value})
+		}
+		output.Body = body
+	}
+
+	return postmanItem{Name: saved.Name, Description: postmanDescription(saved.Description), Request: output}
+}
+
+func postmanRawLanguage(contentType types.RawBodyContentType) string {
+	switch contentType {
+	case types.RawBodyContentTypeJson:
+		return "json"
+	case types.RawBodyContentTypeHtml:
+		return "html"
+	case types.RawBodyContentTypeXml:
+		return "xml"
+	default:
+		return "text"
+	}
+}
+
+func uniquePostmanVariable(variables []postmanVariable, key string) string {
+	exists := func(name string) bool {
+		for _, variable := range variables {
+			if variable.Key == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := key
+	for i := 2; exists(name); i++ {
+		name = fmt.Sprintf("%s%d", key, i)
+	}
+	return name
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	apiStreamFormatNDJSON = "ndjson"
+	apiStreamFormatSSE    = "sse"
+)
+
+// apiStreamClient 流式请求使用的客户端: 只限制等待响应头的时间, 不限制整个响应的时长,
+// 以免 /api/pull 这类长时间的流被中途截断
+var apiStreamClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		ResponseHeaderTimeout: 30 * time.Second,
+	},
+}
+
+// SendHttpRequestStream 以流式模式发送请求, 立即返回请求ID
+// 收到响应头时推送 api_stream_start, 每个 NDJSON 行或 SSE 事件推送 api_stream_chunk,
+// 结束 (包括出错和取消) 时推送 api_stream_done 汇总; 事件负载中带有 requestId 以便前端区分并发请求
+func (d *OllamaApiDebugger) SendHttpRequestStream(request types.ApiRequest, requestID string) (string, error) {
+	if requestID == "" {
+		requestID = GenerateUniqueID()
+	}
+	d.logger.Debug("Received API Debugger stream request", "requestID", requestID, "method", request.Method, "path", request.Path, "serverID", request.SelectedServerID)
+
+	ctx, cancel := context.WithCancel(d.ctx)
+	req, finalURL, err := d.buildHttpRequest(ctx, d.resolveApiVariables(request))
+	if err != nil {
+		cancel()
+		return "", err
+	}
+
+	d.streamsMu.Lock()
+	if _, exists := d.streams[requestID]; exists {
+		d.streamsMu.Unlock()
+		cancel()
+		return "", fmt.Errorf("流式请求已存在: %s", requestID)
+	}
+	d.streams[requestID] = cancel
+	d.streamsMu.Unlock()
+
+	go func() {
+		defer func() {
+			d.streamsMu.Lock()
+			delete(d.streams, requestID)
+			d.streamsMu.Unlock()
+			cancel()
+			if r := recover(); r != nil {
+				d.logger.Error("流式请求goroutine发生恐慌", "panic", r)
+				runtime.EventsEmit(d.ctx, "api_stream_done", types.ApiStreamSummary{RequestID: requestID, Error: fmt.Sprintf("内部错误: %v", r)})
+			}
+		}()
+		response, summary := d.streamHttpRequest(ctx, req, requestID)
+		d.recordHistory(request, response, finalURL, "")
+		d.logger.Debug("API Debugger stream completed", "requestID", requestID, "chunks", summary.ChunkCount, "duration", summary.TotalDurationMs, "cancelled", summary.Cancelled)
+		runtime.EventsEmit(d.ctx, "api_stream_done", summary)
+	}()
+	return requestID, nil
+}
+
+// CancelApiStream 取消进行中的流式请求, 取消后仍会推送 api_stream_done
+func (d *OllamaApiDebugger) CancelApiStream(requestID string) error {
+	d.streamsMu.Lock()
+	cancel, ok := d.streams[requestID]
+	d.streamsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("流式请求不存在或已结束: %s", requestID)
+	}
+	d.logger.Info("取消流式请求", "requestID", requestID)
+	cancel()
+	return nil
+}
+
+// streamHttpRequest 发送请求并逐块推送响应, 返回用于历史记录的响应和流的汇总
+func (d *OllamaApiDebugger) streamHttpRequest(ctx context.Context, req *http.Request, requestID string) (types.ApiResponse, types.ApiStreamSummary) {
+	startTime := time.Now()
+	summary := types.ApiStreamSummary{RequestID: requestID}
+	var response types.ApiResponse
+	finish := func(err error, message string) (types.ApiResponse, types.ApiStreamSummary) {
+		switch {
+		case ctx.Err() != nil:
+			summary.Cancelled = true
+			response.Error = "请求已取消"
+		case err != nil:
+			summary.Error = fmt.Sprintf("%s: %v", message, err)
+			response.Error = summary.Error
+		}
+		summary.TotalDurationMs = time.Since(startTime).Milliseconds()
+		response.RequestDurationMs = summary.TotalDurationMs
+		return response, summary
+	}
+
+	resp, err := apiStreamClient.Do(req)
+	if err != nil {
+		d.logger.Errorf("Failed to send HTTP stream request: %v", err)
+		return finish(err, "发送请求失败")
+	}
+	defer resp.Body.Close()
+
+	summary.StatusCode = resp.StatusCode
+	summary.TimeToFirstByteMs = time.Since(startTime).Milliseconds()
+	response.StatusCode = resp.StatusCode
+	response.StatusText = http.StatusText(resp.StatusCode)
+	response.Headers = responseHeaders(resp.Header)
+
+	format := apiStreamFormatNDJSON
+	if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "text/event-stream") {
+		format = apiStreamFormatSSE
+	}
+	runtime.EventsEmit(d.ctx, "api_stream_start", types.ApiStreamStart{
+		RequestID:         requestID,
+		StatusCode:        resp.StatusCode,
+		StatusText:        response.StatusText,
+		Headers:           response.Headers,
+		Format:            format,
+		TimeToFirstByteMs: summary.TimeToFirstByteMs,
+	})
+
+	emitChunk := func(event, data string) {
+		now := time.Now()
+		if summary.ChunkCount == 0 {
+			summary.TimeToFirstChunkMs = now.Sub(startTime).Milliseconds()
+		}
+		runtime.EventsEmit(d.ctx, "api_stream_chunk", types.ApiStreamChunk{
+			RequestID: requestID,
+			Index:     summary.ChunkCount,
+			Event:     event,
+			Data:      data,
+			Timestamp: now.UnixMilli(),
+			ElapsedMs: now.Sub(startTime).Milliseconds(),
+		})
+		summary.ChunkCount++
+	}
+
+	// SSE 事件由若干 "field: value" 行组成, 以空行结束
+	var sseEvent string
+	var sseData []string
+	dispatchSSE := func() {
+		if len(sseData) > 0 {
+			emitChunk(sseEvent, strings.Join(sseData, "\n"))
+		}
+		sseEvent, sseData = "", nil
+	}
+
+	// 历史记录只保存响应的开头部分, 多读一个字节用于判断是否截断
+	var body strings.Builder
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			summary.Bytes += int64(len(line))
+			if room := maxApiHistoryBodySize + 1 - body.Len(); room > 0 {
+				body.WriteString(line[:min(len(line), room)])
+			}
+
+			line = strings.TrimRight(line, "\r\n")
+			if format == apiStreamFormatNDJSON {
+				if strings.TrimSpace(line) != "" {
+					emitChunk("", line)
+				}
+			} else if line == "" {
+				dispatchSSE()
+			} else if !strings.HasPrefix(line, ":") {
+				field, value, _ := strings.Cut(line, ":")
+				value = strings.TrimPrefix(value, " ")
+				switch field {
+				case "event":
+					sseEvent = value
+				case "data":
+					sseData = append(sseData, value)
+				}
+			}
+		}
+
+		if readErr != nil {
+			response.Body = body.String()
+			if readErr == io.EOF {
+				if format == apiStreamFormatSSE {
+					dispatchSSE()
+				}
+				return finish(nil, "")
+			}
+			if ctx.Err() == nil {
+				d.logger.Errorf("Failed to read response stream: %v", readErr)
+			}
+			return finish(readErr, "读取响应失败")
+		}
+	}
+}
@@ -74,6 +74,7 @@ type OpenAIAdapterManager struct {
 	status types.OpenAIAdapterStatus
 	// mu         sync.Mutex // 移除互斥锁, 简化逻辑
 	configMgr *OllamaConfigManager
+	prompts   *PromptEngineering // 提供 "prompt:<名称>" 虚拟模型
 }
 
 // NewOpenAIAdapterManager 创建一个新的管理器实例
@@ -180,7 +181,7 @@ func (m *OpenAIAdapterManager) Start() error {
 		})
 	}
 
-	mux.Handle("/v1/chat/completions", corsHandler(adapter))
+	mux.Handle("/v1/chat/completions", corsHandler(m.promptModelHandler(adapterLogger, adapter)))
 
 	// Add /v1/models endpoint for compatibility
 	mux.HandleFunc("/v1/models", func(w http.ResponseWriter, r *http.Request) {
@@ -198,9 +199,17 @@ func (m *OpenAIAdapterManager) Start() error {
 			return
 		}
 
+		data := []map[string]interface{}{
+			{"id": m.config.TargetOllamaServerID, "object": "model", "created": 1234567890, "owned_by": "ollama"},
+		}
+		// 已保存的提示词以 "prompt:<名称>" 的形式作为虚拟模型列出
+		for _, id := range m.promptModelIDs() {
+			data = append(data, map[string]interface{}{"id": id, "object": "model", "created": 1234567890, "owned_by": "prompt"})
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"object":"list","data":[{"id":"` + m.config.TargetOllamaServerID + `","object":"model","created":1234567890,"owned_by":"ollama"}]}`))
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "data": data})
 	})
 
 	addr := fmt.Sprintf("%s:%d", m.config.ListenIP, m.config.ListenPort)
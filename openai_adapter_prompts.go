@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+
+	"github.com/16chusi/duolasdk/core"
+)
+
+const (
+	// promptModelPrefix 以该前缀开头的模型名会被解析为已保存的提示词, 如 "prompt:翻译助手" 或 "prompt:<提示词ID>"
+	promptModelPrefix = "prompt:"
+	// promptVariablesField 请求体中传递提示词变量的字段, 转发给 Ollama 之前会被移除
+	promptVariablesField = "prompt_variables"
+	// maxAdapterRequestBody 适配器读取请求体的上限
+	maxAdapterRequestBody = 32 << 20
+)
+
+// SetPromptEngineering 设置提示词模块, 用于把已保存的提示词暴露为虚拟模型
+func (m *OpenAIAdapterManager) SetPromptEngineering(prompts *PromptEngineering) {
+	m.prompts = prompts
+}
+
+// promptModelHandler 拦截 model 为 "prompt:<名称>" 的请求: 用请求中的 prompt_variables 渲染提示词,
+// 作为系统消息插入到消息列表开头, 并把模型替换为提示词配置的第一个模型后交给 next 处理
+func (m *OpenAIAdapterManager) promptModelHandler(logger *core.AppLog, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || m.prompts == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAdapterRequestBody))
+		r.Body.Close()
+		if err != nil {
+			writeOpenAIError(w, http.StatusBadRequest, "failed to read request body: "+err.Error())
+			return
+		}
+
+		rewritten, status, err := m.applyPromptModel(logger, body)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Prompt model request rejected: %v", err))
+			writeOpenAIError(w, status, err.Error())
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(rewritten))
+		r.ContentLength = int64(len(rewritten))
+		r.Header.Set("Content-Length", strconv.Itoa(len(rewritten)))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// applyPromptModel 改写请求体, 非提示词模型的请求原样返回; 出错时返回对应的 HTTP 状态码
+func (m *OpenAIAdapterManager) applyPromptModel(logger *core.AppLog, body []byte) ([]byte, int, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		// 交给下游返回原有的解析错误
+		return body, 0, nil
+	}
+	var model string
+	if err := json.Unmarshal(payload["model"], &model); err != nil || !strings.HasPrefix(model, promptModelPrefix) {
+		return body, 0, nil
+	}
+
+	name := strings.TrimSpace(strings.TrimPrefix(model, promptModelPrefix))
+	prompt, err := m.prompts.FindPrompt(name)
+	if errors.Is(err, errAmbiguousPromptName) {
+		return nil, http.StatusConflict, fmt.Errorf("prompt name %q is ambiguous, use %s<id> instead", name, promptModelPrefix)
+	}
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("prompt %q not found", name)
+	}
+	if len(prompt.Models) == 0 || strings.TrimSpace(prompt.Models[0]) == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("prompt %q has no model configured", prompt.Name)
+	}
+	targetModel := prompt.Models[0]
+
+	values := map[string]interface{}{}
+	if raw, ok := payload[promptVariablesField]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("%s must be an object", promptVariablesField)
+		}
+	}
+	systemPrompt, err := RenderPromptTemplate(prompt, values)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("failed to render prompt %q: %v", prompt.Name, err)
+	}
+
+	var messages []json.RawMessage
+	if raw, ok := payload["messages"]; ok {
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("messages must be an array")
+		}
+	}
+	systemMessage, err := json.Marshal(map[string]string{"role": "system", "content": systemPrompt})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	messages = append([]json.RawMessage{systemMessage}, messages...)
+
+	if payload["messages"], err = json.Marshal(messages); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if payload["model"], err = json.Marshal(targetModel); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	delete(payload, promptVariablesField)
+
+	rewritten, err := json.Marshal(payload)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	logger.Info(fmt.Sprintf("Routing prompt model %q (version %d) to %s", prompt.Name, prompt.Version, targetModel))
+	m.prompts.RecordPromptUsage(types.PromptUsage{
+		PromptID: prompt.ID,
+		Version:  prompt.Version,
+		Source:   types.PromptUsageSourceAdapter,
+		ServerID: m.config.TargetOllamaServerID,
+		Model:    targetModel,
+	})
+	return rewritten, 0, nil
+}
+
+// promptModelIDs 返回可通过适配器调用的虚拟模型名, 只包含配置了模型的提示词
+// 名称重复的提示词改用 "prompt:<ID>", 保证每个虚拟模型名都能唯一解析
+func (m *OpenAIAdapterManager) promptModelIDs() []string {
+	if m.prompts == nil {
+		return nil
+	}
+	prompts, err := m.prompts.ListPrompts()
+	if err != nil {
+		return nil
+	}
+	nameCounts := make(map[string]int, len(prompts))
+	for _, prompt := range prompts {
+		nameCounts[strings.ToLower(prompt.Name)]++
+	}
+	ids := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		if len(prompt.Models) == 0 || strings.TrimSpace(prompt.Models[0]) == "" {
+			continue
+		}
+		if nameCounts[strings.ToLower(prompt.Name)] > 1 {
+			ids = append(ids, promptModelPrefix+prompt.ID)
+		} else {
+			ids = append(ids, promptModelPrefix+prompt.Name)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// writeOpenAIError 以 OpenAI 的错误格式返回错误
+func writeOpenAIError(w http.ResponseWriter, status int, message string) {
+	errType := "invalid_request_error"
+	if status >= http.StatusInternalServerError {
+		errType = "server_error"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"message": message,
+			"type":    errType,
+		},
+	})
+}
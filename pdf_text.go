@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// ExtractPDFText 从 PDF 文件内容中提取文本
+// 只解析未加密、未压缩或 FlateDecode 压缩的内容流中的文本操作符 (Tj / TJ / ' / "),
+// 足以覆盖大多数由办公软件导出的文档; 使用自定义字体编码 (如 CID 字体) 的 PDF 只能提取出部分内容
+func ExtractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\r\n "), []byte("%PDF")) {
+		return "", fmt.Errorf("不是有效的 PDF 文件")
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return "", fmt.Errorf("不支持加密的 PDF 文件")
+	}
+
+	streams, decodeErr, err := pdfContentStreams(data)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	for _, content := range streams {
+		extractPDFContentText(content, &out)
+	}
+
+	text := strings.TrimSpace(pdfBlankLines.ReplaceAllString(out.String(), "\n\n"))
+	if text == "" {
+		if decodeErr != nil {
+			return "", fmt.Errorf("解压 PDF 内容流失败: %w", decodeErr)
+		}
+		return "", fmt.Errorf("未能从 PDF 中提取到文本, 可能是扫描件或使用了不支持的字体编码")
+	}
+	return text, nil
+}
+
+// maxPDFDecodedSize 单个 PDF 所有内容流解压后的总大小上限, 防止压缩炸弹耗尽内存
+const maxPDFDecodedSize = 64 << 20
+
+var (
+	pdfStreamStart = regexp.MustCompile(`stream\r?\n`)
+	pdfBlankLines  = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+)
+
+// pdfContentStreams 找出所有可能包含文本的流并解压
+// decodeErr 为最后一次解压失败的原因, 只在最终提取不到文本时报告; 解压后超过大小上限时返回 err
+func pdfContentStreams(data []byte) (streams [][]byte, decodeErr error, err error) {
+	remaining := int64(maxPDFDecodedSize)
+	for _, loc := range pdfStreamStart.FindAllIndex(data, -1) {
+		if loc[0] >= 3 && string(data[loc[0]-3:loc[0]]) == "end" {
+			continue
+		}
+		end := bytes.Index(data[loc[1]:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		raw := data[loc[1] : loc[1]+end]
+
+		// 流之前的字典描述了过滤器和类型
+		dictStart := bytes.LastIndex(data[:loc[0]], []byte("obj"))
+		if dictStart < 0 {
+			dictStart = 0
+		}
+		dict := string(data[dictStart:loc[0]])
+		if strings.Contains(dict, "/Image") || strings.Contains(dict, "/Length1") || strings.Contains(dict, "/ObjStm") || strings.Contains(dict, "/XRef") {
+			continue
+		}
+
+		switch {
+		case strings.Contains(dict, "/FlateDecode"):
+			reader, zerr := zlib.NewReader(bytes.NewReader(raw))
+			if zerr != nil {
+				decodeErr = zerr
+				continue
+			}
+			decoded, readErr := io.ReadAll(io.LimitReader(reader, remaining+1))
+			reader.Close()
+			if int64(len(decoded)) > remaining {
+				return nil, nil, fmt.Errorf("PDF 内容流解压后超过 %d MB", maxPDFDecodedSize>>20)
+			}
+			remaining -= int64(len(decoded))
+			if readErr != nil {
+				// 部分 PDF 的压缩流末尾不完整, 尽量保留已解压的内容
+				decodeErr = readErr
+			}
+			streams = append(streams, decoded)
+		case strings.Contains(dict, "/Filter"):
+			// 其他过滤器 (DCT / LZW 等) 不包含可提取的文本
+		default:
+			streams = append(streams, raw)
+		}
+	}
+	return streams, decodeErr, nil
+}
+
+// extractPDFContentText 解析内容流中的文本操作符并写入 out
+func extractPDFContentText(content []byte, out *strings.Builder) {
+	var operands []string
+	inArray := false
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, next := readPDFLiteralString(content, i)
+			operands = append(operands, s)
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case c == '<':
+			s, next := readPDFHexString(content, i)
+			operands = append(operands, s)
+			i = next
+		case c == '[':
+			inArray = true
+			operands = nil
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case isPDFSpace(c) || c == '>' || c == '{' || c == '}':
+			i++
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(content) && (content[i] == '.' || (content[i] >= '0' && content[i] <= '9')) {
+				i++
+			}
+			// TJ 数组中较大的负偏移通常表示单词间距
+			if n, err := strconv.ParseFloat(string(content[start:i]), 64); err == nil && inArray && n < -200 {
+				operands = append(operands, " ")
+			}
+		default:
+			start := i
+			for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			if i == start {
+				i++ // 跳过无法识别的分隔符, 如名称前缀 /
+				continue
+			}
+			switch string(content[start:i]) {
+			case "Tj", "TJ":
+				out.WriteString(strings.Join(operands, ""))
+			case "'", "\"":
+				out.WriteString("\n")
+				out.WriteString(strings.Join(operands, ""))
+			case "T*", "Td", "TD", "ET":
+				out.WriteString("\n")
+			}
+			operands = nil
+		}
+	}
+}
+
+// readPDFLiteralString 读取 (...) 形式的字符串, 返回解码后的文本和结束位置
+func readPDFLiteralString(content []byte, start int) (string, int) {
+	var buf []byte
+	depth := 0
+	i := start
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return decodePDFString(buf), i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(content) {
+				return decodePDFString(buf), i
+			}
+			switch e := content[i]; e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r', '\n':
+				// 行尾续行符
+				if e == '\r' && i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					j := i
+					for ; j < len(content) && j < i+3 && content[j] >= '0' && content[j] <= '7'; j++ {
+						n = n*8 + int(content[j]-'0')
+					}
+					buf = append(buf, byte(n))
+					i = j - 1
+				} else {
+					buf = append(buf, e)
+				}
+			}
+			continue
+		}
+		buf = append(buf, c)
+	}
+	return decodePDFString(buf), i
+}
+
+// readPDFHexString 读取 <...> 形式的十六进制字符串
+func readPDFHexString(content []byte, start int) (string, int) {
+	end := bytes.IndexByte(content[start:], '>')
+	if end < 0 {
+		return "", len(content)
+	}
+	var digits []byte
+	for _, c := range content[start+1 : start+end] {
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	buf := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		n, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return "", start + end + 1
+		}
+		buf = append(buf, byte(n))
+	}
+	return decodePDFString(buf), start + end + 1
+}
+
+// decodePDFString 将 PDF 字符串字节转换为文本, 支持 UTF-16BE (带 BOM) 和单字节编码
+func decodePDFString(buf []byte) string {
+	var runes []rune
+	if len(buf) >= 2 && buf[0] == 0xFE && buf[1] == 0xFF {
+		units := make([]uint16, 0, len(buf)/2)
+		for i := 2; i+1 < len(buf); i += 2 {
+			units = append(units, uint16(buf[i])<<8|uint16(buf[i+1]))
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, 0, len(buf))
+		for _, b := range buf {
+			runes = append(runes, rune(b))
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range runes {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
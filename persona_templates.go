@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"tools-ollama/types"
+)
+
+// personaTemplatesKey 对话模板存储的哈希键
+const personaTemplatesKey = "persona_templates"
+
+// ListPersonaTemplates 获取所有对话模板, 按名称排序
+func (cm *ChatManager) ListPersonaTemplates() ([]types.PersonaTemplate, error) {
+	dataMap, err := cm.store.HGetAll(personaTemplatesKey)
+	if err != nil {
+		cm.logger.Error("获取对话模板列表失败", "error", err)
+		return nil, fmt.Errorf("获取对话模板列表失败: %w", err)
+	}
+
+	templates := make([]types.PersonaTemplate, 0, len(dataMap))
+	for _, data := range dataMap {
+		var template types.PersonaTemplate
+		if err := UnmarshalJSONWithError([]byte(data), &template, cm.logger, "解析对话模板"); err != nil {
+			continue
+		}
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// GetPersonaTemplate 获取指定ID的对话模板
+func (cm *ChatManager) GetPersonaTemplate(id string) (*types.PersonaTemplate, error) {
+	data, err := cm.store.HGet(personaTemplatesKey, id)
+	if err != nil || data == "" {
+		return nil, fmt.Errorf("对话模板不存在: %s", id)
+	}
+	var template types.PersonaTemplate
+	if err := UnmarshalJSONWithError([]byte(data), &template, cm.logger, "解析对话模板"); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SavePersonaTemplate 创建或更新对话模板
+func (cm *ChatManager) SavePersonaTemplate(template types.PersonaTemplate) (*types.PersonaTemplate, error) {
+	if err := cm.validatePersonaTemplate(&template); err != nil {
+		cm.logger.Warn("对话模板校验失败", "name", template.Name, "error", err)
+		return nil, err
+	}
+
+	now := GetCurrentTimestamp()
+	if template.ID == "" {
+		template.ID = GenerateUniqueID()
+		template.CreatedAt = now
+	} else if existing, err := cm.GetPersonaTemplate(template.ID); err == nil {
+		template.CreatedAt = existing.CreatedAt
+	}
+	template.UpdatedAt = now
+
+	data, err := MarshalJSONWithError(template, cm.logger, "序列化对话模板")
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.store.HSet(personaTemplatesKey, template.ID, string(data)); err != nil {
+		cm.logger.Error("保存对话模板失败", "id", template.ID, "error", err)
+		return nil, fmt.Errorf("保存对话模板失败: %w", err)
+	}
+	cm.logger.Info("对话模板保存成功", "id", template.ID, "name", template.Name)
+	return &template, nil
+}
+
+// DeletePersonaTemplate 删除对话模板, 已创建的对话不受影响
+func (cm *ChatManager) DeletePersonaTemplate(id string) error {
+	cm.logger.Info("删除对话模板", "id", id)
+	if err := cm.store.HDel(personaTemplatesKey, id); err != nil {
+		cm.logger.Error("删除对话模板失败", "id", id, "error", err)
+		return fmt.Errorf("删除对话模板失败: %w", err)
+	}
+	return nil
+}
+
+// StartConversationFromTemplate 按模板创建并保存一个新对话
+func (cm *ChatManager) StartConversationFromTemplate(templateID string) (*types.Conversation, error) {
+	template, err := cm.GetPersonaTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := MarshalJSONWithError(template.Params, cm.logger, "序列化模型参数")
+	if err != nil {
+		return nil, err
+	}
+	conv := &types.Conversation{
+		Title:       template.Name,
+		ModelName:   template.ModelName,
+		ServerID:    template.ServerID,
+		ModelParams: string(params),
+		Tools:       template.Tools,
+		Messages:    make([]types.Message, 0, len(template.SeedMessages)),
+	}
+
+	if strings.TrimSpace(template.SystemPrompt) != "" {
+		prompt, err := MarshalJSONWithError(types.Prompt{
+			Name:    template.Name,
+			Content: template.SystemPrompt,
+		}, cm.logger, "序列化系统提示词")
+		if err != nil {
+			return nil, err
+		}
+		conv.SystemPrompt = string(prompt)
+	}
+
+	now := GetCurrentTimestamp()
+	for i, msg := range template.SeedMessages {
+		msg.Timestamp = now + int64(i)
+		conv.Messages = append(conv.Messages, msg)
+	}
+
+	cm.logger.Info("从模板创建对话", "templateID", templateID, "model", template.ModelName)
+	return cm.SaveConversation(conv)
+}
+
+// validatePersonaTemplate 校验模板的必填字段、参数范围、预置消息和工具
+func (cm *ChatManager) validatePersonaTemplate(template *types.PersonaTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("模板名称不能为空")
+	}
+	if strings.TrimSpace(template.ModelName) == "" {
+		return fmt.Errorf("模型名称不能为空")
+	}
+	if err := ValidateConversationModelParams(template.Params); err != nil {
+		return err
+	}
+	for i, msg := range template.SeedMessages {
+		switch msg.Role {
+		case "user", "assistant", "system":
+		default:
+			return fmt.Errorf("第 %d 条预置消息的角色无效: %s", i+1, msg.Role)
+		}
+	}
+	if len(template.Tools) > 0 && cm.toolRegistry != nil {
+		if _, err := cm.toolRegistry.Resolve(template.Tools); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,671 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// promptBundleFormat 分享包的格式标识, 导入时用于区分其他 JSON/YAML 文件
+	promptBundleFormat  = "tools-ollama.prompts"
+	promptBundleVersion = 1
+)
+
+// promptFrontMatter Markdown 文件 front matter 中保存的提示词元数据, 正文即提示词内容
+type promptFrontMatter struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Models      []string               `json:"models,omitempty"`
+	CreatedBy   string                 `json:"createdBy,omitempty"`
+	Version     int                    `json:"version,omitempty"`
+	Variables   []types.PromptVariable `json:"variables,omitempty"`
+	CreatedAt   int64                  `json:"createdAt,omitempty"`
+	UpdatedAt   int64                  `json:"updatedAt,omitempty"`
+}
+
+// ExportPrompts 将提示词导出为分享包文本, ids 为空时导出全部提示词
+// Markdown 格式每个文件只能包含一个提示词, 批量导出请使用 ExportPromptsToFile
+func (p *PromptEngineering) ExportPrompts(ids []string, format types.PromptBundleFormat, includeHistory bool) (string, error) {
+	entries, err := p.collectPromptEntries(ids, includeHistory)
+	if err != nil {
+		return "", err
+	}
+	if format == types.PromptBundleFormatMarkdown {
+		if len(entries) != 1 {
+			return "", fmt.Errorf("Markdown 格式每次只能导出一个提示词")
+		}
+		data, err := renderMarkdownPrompt(entries[0].Prompt)
+		return string(data), err
+	}
+
+	data, err := renderPromptBundle(entries, format)
+	if err != nil {
+		return "", err
+	}
+	p.logger.Info("导出提示词完成", "count", len(entries), "format", format, "includeHistory", includeHistory)
+	return string(data), nil
+}
+
+// ExportPromptsToFile 弹出对话框并写入导出文件, 返回写入的路径, 用户取消时返回空字符串
+// Markdown 格式选择目录, 每个提示词写入一个 .md 文件, 便于放入 git 仓库管理
+func (p *PromptEngineering) ExportPromptsToFile(ids []string, format types.PromptBundleFormat, includeHistory bool) (string, error) {
+	entries, err := p.collectPromptEntries(ids, includeHistory)
+	if err != nil {
+		return "", err
+	}
+
+	if format == types.PromptBundleFormatMarkdown {
+		dir, err := runtime.OpenDirectoryDialog(p.ctx, runtime.OpenDialogOptions{
+			Title:                "选择导出提示词的目录",
+			CanCreateDirectories: true,
+		})
+		if err != nil {
+			p.logger.Error("打开目录对话框失败", "error", err)
+			return "", fmt.Errorf("打开目录对话框失败: %w", err)
+		}
+		if dir == "" {
+			return "", nil
+		}
+		if err := writeMarkdownPrompts(dir, entries); err != nil {
+			p.logger.Error("写入提示词文件失败", "dir", dir, "error", err)
+			return "", err
+		}
+		p.logger.Info("提示词已导出到目录", "dir", dir, "count", len(entries))
+		return dir, nil
+	}
+
+	data, err := renderPromptBundle(entries, format)
+	if err != nil {
+		return "", err
+	}
+	ext := "." + string(format)
+	path, err := runtime.SaveFileDialog(p.ctx, runtime.SaveDialogOptions{
+		Title:           "导出提示词",
+		DefaultFilename: "prompts" + ext,
+		Filters: []runtime.FileFilter{
+			{DisplayName: strings.ToUpper(string(format)), Pattern: "*" + ext},
+		},
+	})
+	if err != nil {
+		p.logger.Error("打开保存对话框失败", "error", err)
+		return "", fmt.Errorf("打开保存对话框失败: %w", err)
+	}
+	if path == "" {
+		return "", nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		p.logger.Error("写入导出文件失败", "path", path, "error", err)
+		return "", fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	p.logger.Info("提示词已导出到文件", "path", path, "count", len(entries), "format", format)
+	return path, nil
+}
+
+// SelectPromptImportFiles 弹出文件选择对话框, 返回选中的分享包或 Markdown 文件
+func (p *PromptEngineering) SelectPromptImportFiles() ([]string, error) {
+	paths, err := runtime.OpenMultipleFilesDialog(p.ctx, runtime.OpenDialogOptions{
+		Title: "选择要导入的提示词文件",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "Prompts", Pattern: "*.json;*.yaml;*.yml;*.md;*.markdown"},
+		},
+	})
+	if err != nil {
+		p.logger.Error("打开文件对话框失败", "error", err)
+		return nil, fmt.Errorf("打开文件对话框失败: %w", err)
+	}
+	return paths, nil
+}
+
+// ImportPrompts 从分享包文本导入提示词, format 为空时根据内容自动识别
+func (p *PromptEngineering) ImportPrompts(data string, format types.PromptBundleFormat, strategy types.PromptConflictStrategy, dryRun bool) (types.PromptImportReport, error) {
+	if strings.TrimSpace(data) == "" {
+		return types.PromptImportReport{}, fmt.Errorf("导入内容为空")
+	}
+	entries, err := parsePromptBundle([]byte(data), format, "")
+	if err != nil {
+		p.logger.Error("解析提示词分享包失败", "format", format, "error", err)
+		return types.PromptImportReport{}, err
+	}
+	report := types.PromptImportReport{DryRun: dryRun}
+	if err := p.importPromptEntries(entries, strategy, &report); err != nil {
+		return types.PromptImportReport{}, err
+	}
+	return report, nil
+}
+
+// ImportPromptsFromFiles 从文件或目录导入提示词, 目录中的 .md / .json / .yaml 文件会被递归导入
+// 格式由扩展名决定, 无法解析的文件在报告中记为 invalid
+func (p *PromptEngineering) ImportPromptsFromFiles(paths []string, strategy types.PromptConflictStrategy, dryRun bool) (types.PromptImportReport, error) {
+	p.logger.Info("从文件导入提示词", "paths", paths, "strategy", strategy, "dryRun", dryRun)
+	report := types.PromptImportReport{DryRun: dryRun}
+
+	var entries []types.PromptBundleEntry
+	for _, path := range expandPaths(paths, func(ext string) bool { return promptFileFormat(ext) != "" }) {
+		name := filepath.Base(path)
+		format := promptFileFormat(strings.ToLower(filepath.Ext(path)))
+		data, err := os.ReadFile(path)
+		if err == nil && format == "" {
+			err = fmt.Errorf("不支持的文件类型")
+		}
+		if err == nil {
+			var parsed []types.PromptBundleEntry
+			parsed, err = parsePromptBundle(data, format, strings.TrimSuffix(name, filepath.Ext(name)))
+			entries = append(entries, parsed...)
+		}
+		if err != nil {
+			p.logger.Warn("解析提示词文件失败", "path", path, "error", err)
+			report.Total++
+			report.Invalid++
+			report.Items = append(report.Items, types.PromptImportItem{Name: name, Status: "invalid", Reason: err.Error()})
+		}
+	}
+
+	if err := p.importPromptEntries(entries, strategy, &report); err != nil {
+		return types.PromptImportReport{}, err
+	}
+	return report, nil
+}
+
+// collectPromptEntries 按ID获取提示词及其历史版本, ids 为空时返回全部提示词, 结果按名称排序
+func (p *PromptEngineering) collectPromptEntries(ids []string, includeHistory bool) ([]types.PromptBundleEntry, error) {
+	var prompts []types.Prompt
+	if len(ids) == 0 {
+		all, err := p.ListPrompts()
+		if err != nil {
+			return nil, err
+		}
+		prompts = all
+	} else {
+		for _, id := range ids {
+			prompt, err := p.GetPrompt(id)
+			if err != nil {
+				return nil, fmt.Errorf("提示词不存在: %s", id)
+			}
+			prompts = append(prompts, prompt)
+		}
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("没有可导出的提示词")
+	}
+	sort.SliceStable(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	entries := make([]types.PromptBundleEntry, 0, len(prompts))
+	for _, prompt := range prompts {
+		entry := types.PromptBundleEntry{Prompt: prompt}
+		if includeHistory {
+			revisions, err := p.ListPromptRevisions(prompt.ID)
+			if err != nil {
+				return nil, err
+			}
+			entry.Revisions = revisions
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// importPromptEntries 按冲突策略导入提示词, 结果累加到 report 中
+// 与已有提示词的 ID 相同, 或名称相同 (不区分大小写) 时视为冲突
+func (p *PromptEngineering) importPromptEntries(entries []types.PromptBundleEntry, strategy types.PromptConflictStrategy, report *types.PromptImportReport) error {
+	switch strategy {
+	case "":
+		strategy = types.PromptConflictSkip
+	case types.PromptConflictSkip, types.PromptConflictOverwrite, types.PromptConflictKeepBoth:
+	default:
+		return fmt.Errorf("不支持的冲突处理方式: %s", strategy)
+	}
+
+	existing, err := p.ListPrompts()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]types.Prompt, len(existing))
+	byName := make(map[string]types.Prompt, len(existing))
+	for _, prompt := range existing {
+		byID[prompt.ID] = prompt
+		byName[strings.ToLower(prompt.Name)] = prompt
+	}
+
+	for _, entry := range entries {
+		prompt := entry.Prompt
+		prompt.Name = strings.TrimSpace(prompt.Name)
+		item := types.PromptImportItem{Name: prompt.Name}
+		report.Total++
+
+		if prompt.Name == "" || strings.TrimSpace(prompt.Content) == "" {
+			item.Status, item.Reason = "invalid", "提示词名称和内容不能为空"
+		} else if err := ValidatePromptVariables(prompt.Variables); err != nil {
+			item.Status, item.Reason = "invalid", err.Error()
+		}
+		if item.Status == "invalid" {
+			report.Invalid++
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		conflict, found := byID[prompt.ID]
+		if !found {
+			conflict, found = byName[strings.ToLower(prompt.Name)]
+		}
+
+		var saved types.Prompt
+		switch {
+		case found && strategy == types.PromptConflictSkip:
+			item.Status, item.PromptID, item.Reason = "skipped", conflict.ID, "已存在同名或相同ID的提示词"
+			report.Skipped++
+		case found && strategy == types.PromptConflictOverwrite:
+			item.PromptID = conflict.ID
+			if conflict.Content == prompt.Content && len(promptMetadataChanges(conflict, prompt)) == 0 {
+				item.Status, item.Reason = "skipped", "内容未变化"
+				report.Skipped++
+				break
+			}
+			prompt.ID = conflict.ID
+			saved = prompt
+			if !report.DryRun {
+				if saved, err = p.savePrompt(prompt, "导入覆盖"); err != nil {
+					item.Status, item.Reason = "invalid", err.Error()
+					report.Invalid++
+					break
+				}
+			}
+			item.Status = "overwritten"
+			report.Overwritten++
+		default:
+			item.Status = "new"
+			if found {
+				prompt.ID = ""
+				if name := uniquePromptName(prompt.Name, byName); name != prompt.Name {
+					prompt.Name = name
+					item.Name, item.Status = name, "renamed"
+				}
+			}
+			saved = prompt
+			if !report.DryRun {
+				if saved, err = p.storeImportedPrompt(prompt, entry.Revisions); err != nil {
+					item.Status, item.Reason = "invalid", err.Error()
+					report.Invalid++
+					break
+				}
+				item.PromptID = saved.ID
+			}
+			report.Imported++
+		}
+
+		if saved.Name != "" {
+			// 同一个分享包中的后续条目也要与本次导入的提示词比较
+			if saved.ID != "" {
+				byID[saved.ID] = saved
+			}
+			byName[strings.ToLower(saved.Name)] = saved
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	p.logger.Info("提示词导入完成", "strategy", strategy, "dryRun", report.DryRun, "total", report.Total,
+		"imported", report.Imported, "overwritten", report.Overwritten, "skipped", report.Skipped, "invalid", report.Invalid)
+	return nil
+}
+
+// storeImportedPrompt 保存导入的新提示词, 保留原有的时间戳和历史版本
+// 原ID为空时生成新ID; 分享包中没有历史版本时以当前内容作为一个版本记录
+func (p *PromptEngineering) storeImportedPrompt(prompt types.Prompt, revisions []types.PromptRevision) (types.Prompt, error) {
+	now := GetCurrentTimestamp()
+	if prompt.ID == "" {
+		prompt.ID = GenerateUniqueID()
+	}
+	if prompt.CreatedAt == 0 {
+		prompt.CreatedAt = now
+	}
+	if prompt.UpdatedAt == 0 {
+		prompt.UpdatedAt = now
+	}
+
+	hasCurrent := false
+	for _, revision := range revisions {
+		if revision.Version <= 0 {
+			continue
+		}
+		revision.PromptID = prompt.ID
+		revision.Prompt.ID = prompt.ID
+		if revision.Version > prompt.Version {
+			prompt.Version = revision.Version
+		}
+		hasCurrent = hasCurrent || revision.Version == prompt.Version
+		data, err := json.Marshal(revision)
+		if err != nil {
+			return types.Prompt{}, err
+		}
+		if err := p.store.HSet(promptRevisionsKeyPrefix+prompt.ID, strconv.Itoa(revision.Version), string(data)); err != nil {
+			p.logger.Error("保存导入的历史版本失败", "id", prompt.ID, "version", revision.Version, "error", err)
+			return types.Prompt{}, fmt.Errorf("保存提示词历史版本失败: %w", err)
+		}
+	}
+	if prompt.Version <= 0 {
+		prompt.Version = 1
+	}
+
+	promptJSON, err := json.Marshal(prompt)
+	if err != nil {
+		return types.Prompt{}, err
+	}
+	if err := p.store.HSet("prompts", prompt.ID, string(promptJSON)); err != nil {
+		p.logger.Error("保存导入的提示词失败", "id", prompt.ID, "error", err)
+		return types.Prompt{}, fmt.Errorf("保存提示词失败: %w", err)
+	}
+	if !hasCurrent {
+		if err := p.saveRevision(prompt, "导入"); err != nil {
+			return types.Prompt{}, err
+		}
+	}
+	return prompt, nil
+}
+
+// uniquePromptName 名称已被占用时加上序号, 直到不与已有提示词重名
+func uniquePromptName(name string, byName map[string]types.Prompt) string {
+	if _, exists := byName[strings.ToLower(name)]; !exists {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if _, exists := byName[strings.ToLower(candidate)]; !exists {
+			return candidate
+		}
+	}
+}
+
+// promptFileFormat 根据扩展名确定导入格式, 不支持时返回空字符串
+func promptFileFormat(ext string) types.PromptBundleFormat {
+	switch ext {
+	case ".json":
+		return types.PromptBundleFormatJson
+	case ".yaml", ".yml":
+		return types.PromptBundleFormatYaml
+	case ".md", ".markdown":
+		return types.PromptBundleFormatMarkdown
+	}
+	return ""
+}
+
+// renderPromptBundle 将提示词渲染为 JSON 或 YAML 分享包
+func renderPromptBundle(entries []types.PromptBundleEntry, format types.PromptBundleFormat) ([]byte, error) {
+	bundle := types.PromptBundle{
+		Format:     promptBundleFormat,
+		Version:    promptBundleVersion,
+		ExportedAt: GetCurrentTimestamp(),
+		Prompts:    entries,
+	}
+	switch format {
+	case types.PromptBundleFormatJson:
+		return json.MarshalIndent(bundle, "", "  ")
+	case types.PromptBundleFormatYaml:
+		return MarshalYAML(bundle)
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// renderMarkdownPrompt 将提示词渲染为带 YAML front matter 的 Markdown
+func renderMarkdownPrompt(prompt types.Prompt) ([]byte, error) {
+	frontMatter, err := MarshalYAML(promptFrontMatter{
+		ID:          prompt.ID,
+		Name:        prompt.Name,
+		Description: prompt.Description,
+		Tags:        prompt.Tags,
+		Models:      prompt.Models,
+		CreatedBy:   prompt.CreatedBy,
+		Version:     prompt.Version,
+		Variables:   prompt.Variables,
+		CreatedAt:   prompt.CreatedAt,
+		UpdatedAt:   prompt.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(frontMatter)
+	buf.WriteString("---\n\n")
+	buf.WriteString(strings.TrimRight(prompt.Content, "\n"))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// writeMarkdownPrompts 每个提示词写入一个 Markdown 文件, 文件名由提示词名称生成
+func writeMarkdownPrompts(dir string, entries []types.PromptBundleEntry) error {
+	used := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		base := sanitizeFileName(entry.Name)
+		fileName := base + ".md"
+		for i := 2; used[strings.ToLower(fileName)]; i++ {
+			fileName = fmt.Sprintf("%s-%d.md", base, i)
+		}
+		used[strings.ToLower(fileName)] = true
+
+		data, err := renderMarkdownPrompt(entry.Prompt)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, fileName), data, 0644); err != nil {
+			return fmt.Errorf("写入提示词文件失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// parsePromptBundle 解析分享包, 同时兼容提示词数组、单个提示词和 Markdown 文件
+// fallbackName 用于没有在 front matter 中声明名称的 Markdown 文件
+func parsePromptBundle(data []byte, format types.PromptBundleFormat, fallbackName string) ([]types.PromptBundleEntry, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if format == "" {
+		format = detectPromptBundleFormat(data)
+	}
+
+	switch format {
+	case types.PromptBundleFormatMarkdown:
+		entry, err := parseMarkdownPrompt(data, fallbackName)
+		if err != nil {
+			return nil, err
+		}
+		return []types.PromptBundleEntry{entry}, nil
+	case types.PromptBundleFormatYaml:
+		var value interface{}
+		if err := UnmarshalYAML(data, &value); err != nil {
+			return nil, fmt.Errorf("YAML 格式错误: %w", err)
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return parsePromptBundleJSON(raw)
+	case types.PromptBundleFormatJson:
+		return parsePromptBundleJSON(bytes.TrimSpace(data))
+	default:
+		return nil, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+}
+
+func parsePromptBundleJSON(data []byte) ([]types.PromptBundleEntry, error) {
+	if len(data) > 0 && data[0] == '[' {
+		var entries []types.PromptBundleEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("提示词列表格式错误: %w", err)
+		}
+		return entries, nil
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("分享包格式错误: %w", err)
+	}
+	if _, isBundle := probe["prompts"]; !isBundle {
+		var entry types.PromptBundleEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("提示词格式错误: %w", err)
+		}
+		return []types.PromptBundleEntry{entry}, nil
+	}
+
+	var bundle types.PromptBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("分享包格式错误: %w", err)
+	}
+	if bundle.Format != "" && bundle.Format != promptBundleFormat {
+		return nil, fmt.Errorf("不是提示词分享包: %s", bundle.Format)
+	}
+	if bundle.Version > promptBundleVersion {
+		return nil, fmt.Errorf("分享包版本 %d 过新, 请升级应用后再导入", bundle.Version)
+	}
+	return bundle.Prompts, nil
+}
+
+// parseMarkdownPrompt 解析 Markdown 提示词, front matter 可选
+// 没有声明名称时依次使用 fallbackName 和正文的第一个标题
+func parseMarkdownPrompt(data []byte, fallbackName string) (types.PromptBundleEntry, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var meta promptFrontMatter
+	if frontMatter, body, ok := splitFrontMatter(text); ok {
+		if err := UnmarshalYAML([]byte(frontMatter), &meta); err != nil {
+			return types.PromptBundleEntry{}, fmt.Errorf("front matter 格式错误: %w", err)
+		}
+		text = body
+	}
+
+	content := strings.TrimSpace(text)
+	name := strings.TrimSpace(meta.Name)
+	if name == "" {
+		name = fallbackName
+	}
+	if name == "" {
+		for _, line := range strings.Split(content, "\n") {
+			if strings.HasPrefix(line, "# ") {
+				name = strings.TrimSpace(line[2:])
+				break
+			}
+		}
+	}
+
+	return types.PromptBundleEntry{Prompt: types.Prompt{
+		ID:          meta.ID,
+		Name:        name,
+		Content:     content,
+		Description: meta.Description,
+		CreatedAt:   meta.CreatedAt,
+		UpdatedAt:   meta.UpdatedAt,
+		Models:      meta.Models,
+		Version:     meta.Version,
+		Tags:        meta.Tags,
+		CreatedBy:   meta.CreatedBy,
+		Variables:   meta.Variables,
+	}}, nil
+}
+
+// splitFrontMatter 拆分以 --- 开头并以 --- 或 ... 结束的 front matter
+func splitFrontMatter(text string) (string, string, bool) {
+	if !strings.HasPrefix(text, "---\n") {
+		return "", text, false
+	}
+	lines := strings.SplitAfter(text[4:], "\n")
+	offset := 4
+	for i, line := range lines {
+		if trimmed := strings.TrimRight(line, " \t\n"); trimmed == "---" || trimmed == "..." {
+			return strings.Join(lines[:i], ""), text[offset+len(line):], true
+		}
+		offset += len(line)
+	}
+	return "", text, false
+}
+
+// detectPromptBundleFormat 根据内容猜测格式: JSON 以 { 或 [ 开头, 带 front matter 或标题的视为 Markdown
+func detectPromptBundleFormat(data []byte) types.PromptBundleFormat {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")), bytes.HasPrefix(trimmed, []byte("[")):
+		return types.PromptBundleFormatJson
+	case bytes.HasPrefix(trimmed, []byte("#")) && !bytes.Contains(trimmed, []byte("\nprompts:")):
+		// 以注释开头的 YAML 分享包同样以 # 开头
+		return types.PromptBundleFormatMarkdown
+	}
+	if _, _, ok := splitFrontMatter(strings.ReplaceAll(string(trimmed), "\r\n", "\n")); ok {
+		return types.PromptBundleFormatMarkdown
+	}
+	return types.PromptBundleFormatYaml
+}
+
+// MarshalYAML 将值编码为 YAML, 字段名和顺序与 JSON 编码一致
+func MarshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// JSON 是 YAML 的子集, 先解析为节点树保留字段顺序, 再清除流式风格按块状输出
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalYAML 解析 YAML 并按 JSON 规则填充到 v, 字段名与 JSON 一致
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	if node.Kind == 0 {
+		// 空文档
+		return nil
+	}
+	keepYAMLTimestamps(&node)
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// keepYAMLTimestamps 将日期时间标量按原文作为字符串解析, 避免被转换为 time.Time 后改变格式
+func keepYAMLTimestamps(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepYAMLTimestamps(child)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"tools-ollama/types"
+)
+
+func TestMarshalYAMLRoundTrip(t *testing.T) {
+	contents := []string{
+		"",
+		"plain",
+		"line1\nline2\n",
+		"line1\nline2\n\n\n",
+		"  indented first\nback",
+		"line1\n\t\nline3",
+		"trailing spaces  \n  ",
+		"# not a comment\n- not a list\nkey: not a map",
+		"---\n...\n",
+		"true",
+		"123",
+		"a: b",
+		"中文内容\n  第二行缩进",
+	}
+	for _, content := range contents {
+		want := []types.PromptBundleEntry{{Prompt: types.Prompt{
+			Name:      "翻译: 英文",
+			Content:   content,
+			Version:   3,
+			Tags:      []string{"yes", "1"},
+			Variables: []types.PromptVariable{{Name: "lang", Options: []string{"en", "null"}}},
+		}}}
+		data, err := MarshalYAML(want)
+		if err != nil {
+			t.Fatalf("MarshalYAML(%q): %v", content, err)
+		}
+		var got []types.PromptBundleEntry
+		if err := UnmarshalYAML(data, &got); err != nil {
+			t.Fatalf("UnmarshalYAML(%q): %v\n%s", content, err, data)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip of %q mismatch\nyaml:\n%s\ngot:  %#v\nwant: %#v", content, data, got, want)
+		}
+	}
+}
+
+func TestParseMarkdownPromptFrontMatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		markdown    string
+		wantName    string
+		description string
+		tags        []string
+		models      []string
+	}{
+		{
+			name:     "multi-line plain scalar",
+			markdown: "---\nname: 代码\n  审查\ndescription: first\n  second\n\n  third\n---\nbody",
+			wantName: "代码 审查", description: "first second\nthird",
+		},
+		{
+			name:     "multi-line quoted scalars",
+			markdown: "---\nname: \"a\n  b\"\ndescription: 'it''s\n  fine'\n---\nbody",
+			wantName: "a b", description: "it's fine",
+		},
+		{
+			name:     "anchors and aliases",
+			markdown: "---\nname: x\ntags: &shared\n  - a\n  - b\nmodels: *shared\n---\nbody",
+			wantName: "x", tags: []string{"a", "b"}, models: []string{"a", "b"},
+		},
+		{
+			name:     "timestamp stays text",
+			markdown: "---\nname: 2024-01-01\ndescription: 2024-01-01T08:00:00+08:00\n---\nbody",
+			wantName: "2024-01-01", description: "2024-01-01T08:00:00+08:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := parseMarkdownPrompt([]byte(tt.markdown), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			prompt := entry.Prompt
+			if prompt.Name != tt.wantName || prompt.Description != tt.description || prompt.Content != "body" {
+				t.Errorf("got name %q, description %q, content %q", prompt.Name, prompt.Description, prompt.Content)
+			}
+			if tt.tags != nil && !reflect.DeepEqual(prompt.Tags, tt.tags) {
+				t.Errorf("tags = %q, want %q", prompt.Tags, tt.tags)
+			}
+			if tt.models != nil && !reflect.DeepEqual(prompt.Models, tt.models) {
+				t.Errorf("models = %q, want %q", prompt.Models, tt.models)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings" // 导入 strings 包
+	"sync"
 	"time"
 	"tools-ollama/types"
 
@@ -21,6 +23,7 @@ type PromptEngineering struct {
 	store     *duolasdk.AppStore
 	configMgr *OllamaConfigManager
 	logger    *core.AppLog
+	usageMu   sync.Mutex // 保护使用记录的读改写
 }
 
 // NewPromptPilot 创建一个新的 PromptEngineering 实例
@@ -38,9 +41,12 @@ func (p *PromptEngineering) Startup(ctx context.Context) {
 	p.logger.Info("提示词大师模块已启动")
 }
 
-// GeneratePromptStream 流式生成一个提示词
-func (p *PromptEngineering) GeneratePromptStream(idea string, model string, serverId string) {
-	p.logger.Debug("开始生成提示词流", "idea", idea, "model", model, "serverId", serverId)
+// generatorMetaPrompt 中文元提示词的开头, 完整的系统提示词由 BuildMetaPrompt 根据配置生成
+const generatorMetaPrompt = "你是一位专业的提示词工程大师。你的任务是根据用户提供的想法，创作出一个清晰、具体、高效的提示词，以便用于大型语言模型。"
+
+// GeneratePromptStream 流式生成一个提示词, profileId 为空时使用默认的元提示词配置
+func (p *PromptEngineering) GeneratePromptStream(idea string, model string, serverId string, profileId string) {
+	p.logger.Debug("开始生成提示词流", "idea", idea, "model", model, "serverId", serverId, "profileId", profileId)
 
 	go func() {
 		if idea == "" || model == "" || serverId == "" {
@@ -49,36 +55,18 @@ func (p *PromptEngineering) GeneratePromptStream(idea string, model string, serv
 			return
 		}
 
-		serverConfig, err := p.configMgr.GetServerByID(serverId)
+		callback := func(chunk string) {
+			runtime.EventsEmit(p.ctx, "prompt_pilot_stream", map[string]string{"model": model, "chunk": chunk})
+		}
+
+		profile, err := p.resolveMetaPromptProfile(profileId)
 		if err != nil {
-			p.logger.Error("获取服务器配置失败", "serverId", serverId, "error", err)
+			p.logger.Error("获取元提示词配置失败", "profileId", profileId, "error", err)
 			runtime.EventsEmit(p.ctx, "prompt_pilot_stream_error", map[string]string{"model": model, "error": err.Error()})
 			return
 		}
 
-		p.logger.Debug("使用服务器", "serverName", serverConfig.Name, "baseURL", serverConfig.BaseURL)
-
-		cleanBaseURL := strings.TrimSuffix(serverConfig.BaseURL, "/")
-		finalOllamaURL := cleanBaseURL + "/api"
-		p.logger.Debug("prompt_pilot.go: Final Ollama Base URL being passed to NewOllamaProvider", "url", finalOllamaURL) // Added debug log
-		provider := ai.NewOllamaProvider(p.logger, finalOllamaURL)
-		messages := []types.Message{
-			{
-				Role:    "system",
-				Content: "你是一位专业的提示词工程大师。你的任务是根据用户提供的想法，创作出一个清晰、具体、高效的提示词，以便用于大型语言模型。",
-			},
-			{
-				Role:    "user",
-				Content: fmt.Sprintf("这是我的想法：%s", idea),
-			},
-		}
-
-		callback := func(chunk string) {
-			runtime.EventsEmit(p.ctx, "prompt_pilot_stream", map[string]string{"model": model, "chunk": chunk})
-		}
-
-		coreMessages := ToCoreMessages(messages)
-		if err := provider.ChatStream(model, coreMessages, callback); err != nil {
+		if err := p.streamGeneratedPrompt(serverId, model, profile, idea, callback); err != nil {
 			p.logger.Error("流式生成提示词失败", "model", model, "error", err)
 			runtime.EventsEmit(p.ctx, "prompt_pilot_stream_error", map[string]string{"model": model, "error": err.Error()})
 			return
@@ -89,34 +77,133 @@ func (p *PromptEngineering) GeneratePromptStream(idea string, model string, serv
 	}()
 }
 
-// OptimizePrompt 优化一个提示词
-func (p *PromptEngineering) OptimizePrompt(content string, feedback string, model string, serverId string) (string, error) {
+// optimizeMetaPrompt 提示词优化使用的系统提示词
+const optimizeMetaPrompt = `你是一位专业的提示词工程大师。你的任务是根据用户的修改意见改进给定的提示词, 使其更清晰、具体、结构化, 并尽量保留原有的意图和 {{变量}} 占位符。
+只输出改进后的完整提示词本身, 不要添加任何解释、前言或代码块标记。`
+
+// streamGeneratedPrompt 在指定服务器的模型上根据想法流式生成提示词
+func (p *PromptEngineering) streamGeneratedPrompt(serverId string, model string, profile types.MetaPromptProfile, idea string, callback func(string)) error {
+	ollamaURL, err := p.ollamaAPIURL(serverId)
+	if err != nil {
+		return err
+	}
+	p.logger.Debug("使用服务器生成提示词", "serverId", serverId, "url", ollamaURL, "profileId", profile.ID)
+
+	messages := []types.Message{
+		{Role: "system", Content: BuildMetaPrompt(profile)},
+		{Role: "user", Content: metaPromptUserMessage(profile, idea)},
+	}
+	return ai.NewOllamaProvider(p.logger, ollamaURL).ChatStream(model, ToCoreMessages(messages), callback)
+}
+
+// OptimizePrompt 调用模型按反馈意见优化提示词
+// 优化过程通过 prompt_optimize_stream 事件流式推送, 完成后返回优化结果以及与原文的逐行差异
+func (p *PromptEngineering) OptimizePrompt(content string, feedback string, model string, serverId string) (types.PromptOptimizeResult, error) {
 	p.logger.Debug("开始优化提示词", "model", model, "serverId", serverId)
 
-	if content == "" || feedback == "" || model == "" || serverId == "" {
-		return "", fmt.Errorf("参数不能为空")
+	if strings.TrimSpace(content) == "" || model == "" || serverId == "" {
+		return types.PromptOptimizeResult{}, fmt.Errorf("参数不能为空")
 	}
 
-	// 此处为简化实现，实际可调用AI进行优化
-	optimizedPrompt := fmt.Sprintf("%s\n\n--- 优化建议 ---\n%s\n\n--- 基于建议优化后的版本 ---", content, feedback)
-	p.logger.Debug("提示词优化完成")
+	ollamaURL, err := p.ollamaAPIURL(serverId)
+	if err != nil {
+		return types.PromptOptimizeResult{}, err
+	}
+	provider := ai.NewOllamaProvider(p.logger, ollamaURL)
+
+	if strings.TrimSpace(feedback) == "" {
+		feedback = "请全面改进这个提示词的清晰度、结构和约束条件。"
+	}
+	messages := []types.Message{
+		{Role: "system", Content: optimizeMetaPrompt},
+		{Role: "user", Content: fmt.Sprintf("原始提示词:\n%s\n\n修改意见:\n%s", content, feedback)},
+	}
+
+	var optimized strings.Builder
+	callback := func(chunk string) {
+		optimized.WriteString(chunk)
+		runtime.EventsEmit(p.ctx, "prompt_optimize_stream", map[string]string{"model": model, "chunk": chunk})
+	}
+	if err := provider.ChatStream(model, ToCoreMessages(messages), callback); err != nil {
+		p.logger.Error("优化提示词失败", "model", model, "error", err)
+		runtime.EventsEmit(p.ctx, "prompt_optimize_error", map[string]string{"model": model, "error": err.Error()})
+		return types.PromptOptimizeResult{}, fmt.Errorf("优化提示词失败: %w", err)
+	}
+
+	_, text := SplitThinkTags(optimized.String())
+	text = stripCodeFence(text)
+	if text == "" {
+		return types.PromptOptimizeResult{}, fmt.Errorf("模型没有返回优化后的提示词")
+	}
 
-	return optimizedPrompt, nil
+	result := types.PromptOptimizeResult{
+		Original:  content,
+		Optimized: text,
+		Diff:      DiffText(content, text),
+	}
+	runtime.EventsEmit(p.ctx, "prompt_optimize_done", result)
+	p.logger.Debug("提示词优化完成", "added", result.Diff.Added, "removed", result.Diff.Removed)
+	return result, nil
+}
+
+// ollamaAPIURL 返回指定服务器的 Ollama API 地址 (BaseURL + /api)
+func (p *PromptEngineering) ollamaAPIURL(serverId string) (string, error) {
+	serverConfig, err := p.configMgr.GetServerByID(serverId)
+	if err != nil {
+		p.logger.Error("获取服务器配置失败", "serverId", serverId, "error", err)
+		return "", err
+	}
+	return strings.TrimSuffix(serverConfig.BaseURL, "/") + "/api", nil
+}
+
+// stripCodeFence 去掉模型输出外层的 ``` 代码块标记
+func stripCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	text = strings.TrimSuffix(text, "```")
+	if idx := strings.Index(text, "\n"); idx >= 0 {
+		text = text[idx+1:]
+	} else {
+		text = strings.TrimPrefix(text, "```")
+	}
+	return strings.TrimSpace(text)
 }
 
-// SavePrompt 保存一个提示词到存储
+// SavePrompt 保存一个提示词到存储, 每次保存都会记录一个不可变的历史版本
 func (p *PromptEngineering) SavePrompt(prompt types.Prompt) error {
+	_, err := p.savePrompt(prompt, "")
+	return err
+}
+
+// savePrompt 保存提示词并记录历史版本, 返回保存后的提示词
+// 版本号以存储中的当前版本为准, 避免前端持有旧数据时产生重复版本
+func (p *PromptEngineering) savePrompt(prompt types.Prompt, note string) (types.Prompt, error) {
 	p.logger.Debug("准备保存提示词", "promptName", prompt.Name)
 
+	if err := ValidatePromptVariables(prompt.Variables); err != nil {
+		p.logger.Warn("提示词变量定义无效", "promptName", prompt.Name, "error", err)
+		return types.Prompt{}, err
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
 	if prompt.ID == "" {
 		prompt.ID = uuid.New().String()
-		now := time.Now().UnixNano() / int64(time.Millisecond)
 		prompt.CreatedAt = now
 		prompt.UpdatedAt = now
 		prompt.Version = 1
 		p.logger.Debug("创建新提示词", "id", prompt.ID)
 	} else {
-		prompt.UpdatedAt = time.Now().UnixNano() / int64(time.Millisecond)
+		if existing, err := p.GetPrompt(prompt.ID); err == nil {
+			// 引入历史版本之前保存的提示词没有版本记录, 先补录当前版本
+			if err := p.ensureRevision(existing); err != nil {
+				return types.Prompt{}, err
+			}
+			prompt.CreatedAt = existing.CreatedAt
+			prompt.Version = existing.Version
+		}
+		prompt.UpdatedAt = now
 		prompt.Version++
 		p.logger.Debug("更新提示词", "id", prompt.ID, "newVersion", prompt.Version)
 	}
@@ -124,16 +211,19 @@ func (p *PromptEngineering) SavePrompt(prompt types.Prompt) error {
 	promptJSON, err := json.Marshal(prompt)
 	if err != nil {
 		p.logger.Error("序列化提示词失败", "error", err)
-		return err
+		return types.Prompt{}, err
 	}
 
 	if err := p.store.HSet("prompts", prompt.ID, string(promptJSON)); err != nil {
 		p.logger.Error("保存提示词到数据库失败", "error", err)
-		return err
+		return types.Prompt{}, err
+	}
+	if err := p.saveRevision(prompt, note); err != nil {
+		return types.Prompt{}, err
 	}
 
-	p.logger.Info("提示词保存成功", "promptName", prompt.Name, "id", prompt.ID)
-	return nil
+	p.logger.Info("提示词保存成功", "promptName", prompt.Name, "id", prompt.ID, "version", prompt.Version)
+	return prompt, nil
 }
 
 // ListPrompts 返回所有已保存的提示词
@@ -180,6 +270,37 @@ func (p *PromptEngineering) GetPrompt(id string) (types.Prompt, error) {
 	return prompt, nil
 }
 
+// errAmbiguousPromptName 多个提示词同名时按名称查找返回的错误
+var errAmbiguousPromptName = errors.New("提示词名称不唯一")
+
+// FindPrompt 按ID或名称 (不区分大小写) 查找提示词, 名称对应多个提示词时返回错误, 需要改用ID
+func (p *PromptEngineering) FindPrompt(nameOrID string) (types.Prompt, error) {
+	prompts, err := p.ListPrompts()
+	if err != nil {
+		return types.Prompt{}, err
+	}
+	var matches []types.Prompt
+	for _, prompt := range prompts {
+		if prompt.ID == nameOrID {
+			return prompt, nil
+		}
+		if strings.EqualFold(prompt.Name, nameOrID) {
+			matches = append(matches, prompt)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return types.Prompt{}, fmt.Errorf("提示词不存在: %s", nameOrID)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, prompt := range matches {
+		ids[i] = prompt.ID
+	}
+	return types.Prompt{}, fmt.Errorf("%w: %s 对应 %d 个提示词, 请改用ID (%s)", errAmbiguousPromptName, nameOrID, len(matches), strings.Join(ids, ", "))
+}
+
 // DeletePrompt 根据ID删除一个提示词
 func (p *PromptEngineering) DeletePrompt(id string) error {
 	p.logger.Debug("开始删除提示词", "id", id)
@@ -188,7 +309,34 @@ func (p *PromptEngineering) DeletePrompt(id string) error {
 		p.logger.Error("从数据库删除提示词失败", "id", id, "error", err)
 		return err
 	}
+	p.deleteRevisions(id)
+	p.deleteSuite(id)
+	p.deleteUsage(id)
 
 	p.logger.Info("提示词删除成功", "id", id)
 	return nil
 }
+
+// GetPromptVariables 返回提示词中需要填写的变量, 供前端生成表单
+func (p *PromptEngineering) GetPromptVariables(id string) ([]types.PromptVariable, error) {
+	prompt, err := p.GetPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	return ResolvePromptVariables(prompt), nil
+}
+
+// RenderPrompt 使用给定的变量值渲染提示词
+func (p *PromptEngineering) RenderPrompt(id string, values map[string]interface{}) (string, error) {
+	p.logger.Debug("开始渲染提示词", "id", id, "valueCount", len(values))
+	prompt, err := p.GetPrompt(id)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := RenderPromptTemplate(prompt, values)
+	if err != nil {
+		p.logger.Warn("渲染提示词失败", "id", id, "error", err)
+		return "", err
+	}
+	return rendered, nil
+}
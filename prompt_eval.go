@@ -0,0 +1,387 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/16chusi/duolasdk/core/ai"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// promptSuitesKey 测试套件存储的哈希键, 字段为提示词ID
+	promptSuitesKey = "prompt_suites"
+	// promptSuiteRunsKeyPrefix 套件运行记录的哈希键前缀, 每个提示词一个哈希
+	promptSuiteRunsKeyPrefix = "prompt_suite_runs:"
+	// judgePassScore 评审得分达到该值视为通过
+	judgePassScore = 0.7
+)
+
+// judgeMetaPrompt LLM 评审使用的系统提示词
+const judgeMetaPrompt = `你是一名严格的评审。请根据评分标准评价模型的回答, 给出 0 到 10 的整数分数。
+只输出一个 JSON 对象, 格式为 {"score": 分数, "reason": "简短理由"}, 不要输出其他内容。`
+
+// GetPromptSuite 获取提示词的测试套件, 不存在时返回空套件
+func (p *PromptEngineering) GetPromptSuite(promptID string) (types.PromptSuite, error) {
+	suite := types.PromptSuite{PromptID: promptID, Cases: []types.PromptTestCase{}}
+	data, err := p.store.HGet(promptSuitesKey, promptID)
+	if err != nil || data == "" {
+		return suite, nil
+	}
+	if err := json.Unmarshal([]byte(data), &suite); err != nil {
+		p.logger.Error("解析测试套件失败", "promptID", promptID, "error", err)
+		return suite, fmt.Errorf("解析测试套件失败: %w", err)
+	}
+	return suite, nil
+}
+
+// SavePromptSuite 保存提示词的测试套件
+func (p *PromptEngineering) SavePromptSuite(suite types.PromptSuite) (types.PromptSuite, error) {
+	if _, err := p.GetPrompt(suite.PromptID); err != nil {
+		return suite, fmt.Errorf("提示词不存在: %s", suite.PromptID)
+	}
+	for i := range suite.Cases {
+		tc := &suite.Cases[i]
+		if tc.ID == "" {
+			tc.ID = GenerateUniqueID()
+		}
+		if strings.TrimSpace(tc.Name) == "" {
+			tc.Name = fmt.Sprintf("用例 %d", i+1)
+		}
+		if err := validateExpectations(tc.Expectations, suite.Judge); err != nil {
+			return suite, fmt.Errorf("%s: %w", tc.Name, err)
+		}
+	}
+	suite.UpdatedAt = GetCurrentTimestamp()
+
+	data, err := json.Marshal(suite)
+	if err != nil {
+		p.logger.Error("序列化测试套件失败", "error", err)
+		return suite, err
+	}
+	if err := p.store.HSet(promptSuitesKey, suite.PromptID, string(data)); err != nil {
+		p.logger.Error("保存测试套件失败", "promptID", suite.PromptID, "error", err)
+		return suite, fmt.Errorf("保存测试套件失败: %w", err)
+	}
+	p.logger.Info("测试套件保存成功", "promptID", suite.PromptID, "caseCount", len(suite.Cases))
+	return suite, nil
+}
+
+// RunPromptSuite 在一个或多个模型上执行提示词的测试套件, 计算得分并保存运行记录
+// 结果会与较早版本的最近一次运行对比; 每完成一个用例通过 prompt_suite_progress 事件推送进度
+func (p *PromptEngineering) RunPromptSuite(promptID string, models []types.ModelTarget) (types.PromptSuiteRun, error) {
+	p.logger.Info("开始运行测试套件", "promptID", promptID, "models", models)
+
+	if len(models) == 0 {
+		return types.PromptSuiteRun{}, fmt.Errorf("至少需要选择一个模型")
+	}
+	prompt, err := p.GetPrompt(promptID)
+	if err != nil {
+		return types.PromptSuiteRun{}, fmt.Errorf("提示词不存在: %s", promptID)
+	}
+	suite, err := p.GetPromptSuite(promptID)
+	if err != nil {
+		return types.PromptSuiteRun{}, err
+	}
+	if len(suite.Cases) == 0 {
+		return types.PromptSuiteRun{}, fmt.Errorf("测试套件中没有用例")
+	}
+
+	start := time.Now()
+	run := types.PromptSuiteRun{
+		ID:            GenerateUniqueID(),
+		PromptID:      promptID,
+		PromptVersion: prompt.Version,
+		StartedAt:     GetCurrentTimestamp(),
+	}
+
+	total := len(models) * len(suite.Cases)
+	for _, target := range models {
+		score := types.PromptTargetScore{Target: target, Total: len(suite.Cases)}
+		for _, tc := range suite.Cases {
+			result := p.runPromptCase(prompt, tc, target, suite.Judge)
+			run.Results = append(run.Results, result)
+			score.Score += result.Score
+			if result.Passed {
+				score.Passed++
+			}
+			runtime.EventsEmit(p.ctx, "prompt_suite_progress", map[string]interface{}{
+				"promptId": promptID,
+				"runId":    run.ID,
+				"current":  len(run.Results),
+				"total":    total,
+				"result":   result,
+			})
+		}
+		score.Score /= float64(len(suite.Cases))
+		run.Scores = append(run.Scores, score)
+		run.Score += score.Score
+	}
+	run.Score /= float64(len(models))
+	run.DurationMs = time.Since(start).Milliseconds()
+
+	if baseline := p.findBaselineRun(promptID, prompt.Version); baseline != nil {
+		compareWithBaseline(&run, baseline)
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		p.logger.Error("序列化套件运行结果失败", "error", err)
+		return run, err
+	}
+	if err := p.store.HSet(promptSuiteRunsKeyPrefix+promptID, run.ID, string(data)); err != nil {
+		p.logger.Error("保存套件运行结果失败", "promptID", promptID, "error", err)
+		return run, fmt.Errorf("保存套件运行结果失败: %w", err)
+	}
+
+	p.logger.Info("测试套件运行完成", "promptID", promptID, "score", run.Score, "durationMs", run.DurationMs)
+	return run, nil
+}
+
+// ListPromptSuiteRuns 获取提示词的所有套件运行记录, 最新的在前
+func (p *PromptEngineering) ListPromptSuiteRuns(promptID string) ([]types.PromptSuiteRun, error) {
+	dataMap, err := p.store.HGetAll(promptSuiteRunsKeyPrefix + promptID)
+	if err != nil {
+		p.logger.Error("获取套件运行记录失败", "promptID", promptID, "error", err)
+		return nil, fmt.Errorf("获取套件运行记录失败: %w", err)
+	}
+
+	runs := make([]types.PromptSuiteRun, 0, len(dataMap))
+	for _, data := range dataMap {
+		var run types.PromptSuiteRun
+		if err := json.Unmarshal([]byte(data), &run); err != nil {
+			p.logger.Warn("解析套件运行记录失败", "promptID", promptID, "error", err)
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt > runs[j].StartedAt })
+	return runs, nil
+}
+
+// deleteSuite 删除提示词的测试套件及其运行记录
+func (p *PromptEngineering) deleteSuite(promptID string) {
+	if err := p.store.HDel(promptSuitesKey, promptID); err != nil {
+		p.logger.Warn("删除测试套件失败", "promptID", promptID, "error", err)
+	}
+	dataMap, err := p.store.HGetAll(promptSuiteRunsKeyPrefix + promptID)
+	if err != nil {
+		return
+	}
+	for runID := range dataMap {
+		p.store.HDel(promptSuiteRunsKeyPrefix+promptID, runID)
+	}
+}
+
+// runPromptCase 在单个模型上执行单个用例并检查所有期望
+func (p *PromptEngineering) runPromptCase(prompt types.Prompt, tc types.PromptTestCase, target types.ModelTarget, judge types.ModelTarget) (result types.PromptCaseResult) {
+	result = types.PromptCaseResult{CaseID: tc.ID, CaseName: tc.Name, Target: target, Checks: []types.PromptCheckResult{}}
+	start := time.Now()
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	rendered, err := RenderPromptTemplate(prompt, tc.Variables)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	messages := []types.Message{{Role: "user", Content: rendered}}
+	if strings.TrimSpace(tc.Input) != "" {
+		messages = []types.Message{
+			{Role: "system", Content: rendered},
+			{Role: "user", Content: tc.Input},
+		}
+	}
+
+	output, err := p.chatOnce(target, messages)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = output
+
+	var totalWeight float64
+	result.Passed = true
+	for _, expectation := range tc.Expectations {
+		check := p.checkExpectation(expectation, output, tc, judge)
+		weight := expectation.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		totalWeight += weight
+		result.Score += check.Score * weight
+		result.Passed = result.Passed && check.Passed
+		result.Checks = append(result.Checks, check)
+	}
+	if totalWeight > 0 {
+		result.Score /= totalWeight
+	} else {
+		// 没有期望的用例只检查能否正常得到回复
+		result.Score = 1
+	}
+	return result
+}
+
+// checkExpectation 检查单项期望
+func (p *PromptEngineering) checkExpectation(expectation types.PromptExpectation, output string, tc types.PromptTestCase, judge types.ModelTarget) types.PromptCheckResult {
+	check := types.PromptCheckResult{Type: expectation.Type}
+	pass := func(ok bool, detail string) types.PromptCheckResult {
+		check.Passed = ok
+		if ok {
+			check.Score = 1
+		}
+		check.Detail = detail
+		return check
+	}
+
+	switch expectation.Type {
+	case "contains":
+		return pass(strings.Contains(output, expectation.Value), "")
+	case "not_contains":
+		return pass(!strings.Contains(output, expectation.Value), "")
+	case "regex":
+		re, err := regexp.Compile(expectation.Value)
+		if err != nil {
+			return pass(false, fmt.Sprintf("正则无效: %v", err))
+		}
+		return pass(re.MatchString(output), "")
+	case "json_schema":
+		schema, err := ParseJSONSchema(expectation.Value)
+		if err != nil {
+			return pass(false, err.Error())
+		}
+		if _, err := parseStructuredOutput(output, schema); err != nil {
+			return pass(false, err.Error())
+		}
+		return pass(true, "")
+	case "judge":
+		score, reason, err := p.judgeOutput(judge, expectation.Value, tc, output)
+		if err != nil {
+			return pass(false, fmt.Sprintf("评审失败: %v", err))
+		}
+		check.Score = score
+		check.Passed = score >= judgePassScore
+		check.Detail = reason
+		return check
+	}
+	return pass(false, fmt.Sprintf("未知的期望类型: %s", expectation.Type))
+}
+
+// judgeOutput 让评审模型按评分标准给回答打分, 返回 0 到 1 的分数
+func (p *PromptEngineering) judgeOutput(judge types.ModelTarget, rubric string, tc types.PromptTestCase, output string) (float64, string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "评分标准:\n%s\n\n", rubric)
+	if tc.Input != "" {
+		fmt.Fprintf(&sb, "用户输入:\n%s\n\n", tc.Input)
+	}
+	fmt.Fprintf(&sb, "模型回答:\n%s", output)
+
+	reply, err := p.chatOnce(judge, []types.Message{
+		{Role: "system", Content: judgeMetaPrompt},
+		{Role: "user", Content: sb.String()},
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	var verdict struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(reply)), &verdict); err != nil {
+		return 0, "", fmt.Errorf("无法解析评审结果: %s", reply)
+	}
+	return min(max(verdict.Score/10, 0), 1), verdict.Reason, nil
+}
+
+// chatOnce 在指定服务器的模型上执行一次阻塞式对话, 返回去掉思考过程后的回复
+func (p *PromptEngineering) chatOnce(target types.ModelTarget, messages []types.Message) (string, error) {
+	if target.Model == "" || target.ServerID == "" {
+		return "", fmt.Errorf("模型和服务器不能为空")
+	}
+	ollamaURL, err := p.ollamaAPIURL(target.ServerID)
+	if err != nil {
+		return "", err
+	}
+	reply, err := ai.NewOllamaProvider(p.logger, ollamaURL).Chat(target.Model, ToCoreMessages(messages))
+	if err != nil {
+		return "", err
+	}
+	_, content := SplitThinkTags(reply)
+	return content, nil
+}
+
+// extractJSONObject 从模型回复中取出最外层的 JSON 对象, 兼容代码块和前后多余的说明文字
+func extractJSONObject(reply string) string {
+	text := stripCodeFence(reply)
+	if start, end := strings.Index(text, "{"), strings.LastIndex(text, "}"); start >= 0 && end > start {
+		return text[start : end+1]
+	}
+	return text
+}
+
+// findBaselineRun 查找对比基准: 优先取较早版本的最近一次运行, 没有时取当前版本的上一次运行
+func (p *PromptEngineering) findBaselineRun(promptID string, version int) *types.PromptSuiteRun {
+	runs, err := p.ListPromptSuiteRuns(promptID)
+	if err != nil || len(runs) == 0 {
+		return nil
+	}
+	for i := range runs {
+		if runs[i].PromptVersion < version {
+			return &runs[i]
+		}
+	}
+	return &runs[0]
+}
+
+// compareWithBaseline 计算本次运行与基准运行的得分差
+func compareWithBaseline(run *types.PromptSuiteRun, baseline *types.PromptSuiteRun) {
+	run.BaselineRunID = baseline.ID
+	run.BaselineVersion = baseline.PromptVersion
+	delta := run.Score - baseline.Score
+	run.ScoreDelta = &delta
+
+	for i := range run.Scores {
+		for _, previous := range baseline.Scores {
+			if previous.Target == run.Scores[i].Target {
+				d := run.Scores[i].Score - previous.Score
+				run.Scores[i].Delta = &d
+				break
+			}
+		}
+	}
+}
+
+// validateExpectations 校验期望配置, 正则和 JSON Schema 需要能正确解析
+func validateExpectations(expectations []types.PromptExpectation, judge types.ModelTarget) error {
+	for _, expectation := range expectations {
+		switch expectation.Type {
+		case "contains", "not_contains":
+			if expectation.Value == "" {
+				return fmt.Errorf("%s 期望的内容不能为空", expectation.Type)
+			}
+		case "regex":
+			if _, err := regexp.Compile(expectation.Value); err != nil {
+				return fmt.Errorf("正则无效: %w", err)
+			}
+		case "json_schema":
+			if _, err := ParseJSONSchema(expectation.Value); err != nil {
+				return err
+			}
+		case "judge":
+			if strings.TrimSpace(expectation.Value) == "" {
+				return fmt.Errorf("评审期望需要评分标准")
+			}
+			if judge.Model == "" || judge.ServerID == "" {
+				return fmt.Errorf("使用评审期望时需要设置评审模型")
+			}
+		default:
+			return fmt.Errorf("未知的期望类型: %s", expectation.Type)
+		}
+	}
+	return nil
+}
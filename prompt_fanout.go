@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// rankMetaPrompt 评审模型为候选提示词排名时使用的系统提示词
+const rankMetaPrompt = `你是一位提示词评审专家。请根据用户的原始想法, 评价每个候选提示词的清晰度、具体程度和可执行性, 给出 0 到 10 的分数并排名。
+只输出一个 JSON 对象, 格式为 {"ranking": [{"index": 候选编号, "score": 分数, "reason": "简短理由"}]}, 按分数从高到低排列, 不要输出其他内容。`
+
+// GeneratePromptCandidates 在多个模型/服务器上并发生成候选提示词, 可选地由评审模型排名
+// 立即返回请求ID, 过程通过以下事件推送, 负载中都带有 requestId:
+//   - prompt_candidates_stream: 单个候选的内容分块 (index / chunk)
+//   - prompt_candidates_candidate_done: 单个候选生成结束 (PromptCandidate)
+//   - prompt_candidates_done: 全部结束, 包含排名后的 PromptGenerationResult
+func (p *PromptEngineering) GeneratePromptCandidates(req types.PromptGenerationRequest) (string, error) {
+	if strings.TrimSpace(req.Idea) == "" {
+		return "", fmt.Errorf("想法不能为空")
+	}
+	if len(req.Targets) == 0 {
+		return "", fmt.Errorf("至少需要选择一个生成模型")
+	}
+	targets := append([]types.ModelTarget{}, req.Targets...)
+	if req.Judge != nil {
+		targets = append(targets, *req.Judge)
+	}
+	for _, target := range targets {
+		if target.Model == "" || target.ServerID == "" {
+			return "", fmt.Errorf("模型和服务器不能为空")
+		}
+	}
+	profile, err := p.resolveMetaPromptProfile(req.ProfileID)
+	if err != nil {
+		return "", err
+	}
+	if req.RequestID == "" {
+		req.RequestID = GenerateUniqueID()
+	}
+	p.logger.Debug("开始多模型生成提示词", "requestID", req.RequestID, "targets", req.Targets, "judge", req.Judge, "profileId", profile.ID)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				p.logger.Error("多模型生成goroutine发生恐慌", "panic", r)
+				runtime.EventsEmit(p.ctx, "prompt_candidates_done", types.PromptGenerationResult{RequestID: req.RequestID, JudgeError: fmt.Sprintf("内部错误: %v", r)})
+			}
+		}()
+
+		result := types.PromptGenerationResult{
+			RequestID:  req.RequestID,
+			Candidates: p.generateCandidates(req, profile),
+		}
+		if req.Judge != nil {
+			if err := p.rankCandidates(*req.Judge, req.Idea, result.Candidates); err != nil {
+				p.logger.Warn("候选提示词排名失败", "requestID", req.RequestID, "error", err)
+				result.JudgeError = err.Error()
+			} else {
+				result.Judged = true
+			}
+		}
+
+		runtime.EventsEmit(p.ctx, "prompt_candidates_done", result)
+		p.logger.Debug("多模型生成提示词完成", "requestID", req.RequestID, "judged", result.Judged)
+	}()
+	return req.RequestID, nil
+}
+
+// generateCandidates 并发地在每个目标模型上生成候选提示词
+func (p *PromptEngineering) generateCandidates(req types.PromptGenerationRequest, profile types.MetaPromptProfile) []types.PromptCandidate {
+	candidates := make([]types.PromptCandidate, len(req.Targets))
+	var wg sync.WaitGroup
+	for i, target := range req.Targets {
+		wg.Add(1)
+		go func(index int, target types.ModelTarget) {
+			defer wg.Done()
+			candidate := types.PromptCandidate{Index: index, Target: target}
+			start := time.Now()
+
+			var content strings.Builder
+			err := p.streamGeneratedPrompt(target.ServerID, target.Model, profile, req.Idea, func(chunk string) {
+				content.WriteString(chunk)
+				runtime.EventsEmit(p.ctx, "prompt_candidates_stream", map[string]interface{}{
+					"requestId": req.RequestID,
+					"index":     index,
+					"serverId":  target.ServerID,
+					"model":     target.Model,
+					"chunk":     chunk,
+				})
+			})
+			if err != nil {
+				p.logger.Error("候选提示词生成失败", "model", target.Model, "serverId", target.ServerID, "error", err)
+				candidate.Error = err.Error()
+			}
+			_, candidate.Content = SplitThinkTags(content.String())
+			candidate.DurationMs = time.Since(start).Milliseconds()
+			candidates[index] = candidate
+
+			runtime.EventsEmit(p.ctx, "prompt_candidates_candidate_done", map[string]interface{}{
+				"requestId": req.RequestID,
+				"candidate": candidate,
+			})
+		}(i, target)
+	}
+	wg.Wait()
+	return candidates
+}
+
+// rankCandidates 让评审模型为生成成功的候选提示词打分排名, 结果写回 candidates
+func (p *PromptEngineering) rankCandidates(judge types.ModelTarget, idea string, candidates []types.PromptCandidate) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "原始想法:\n%s\n", idea)
+	valid := 0
+	for _, candidate := range candidates {
+		if candidate.Error != "" || strings.TrimSpace(candidate.Content) == "" {
+			continue
+		}
+		valid++
+		fmt.Fprintf(&sb, "\n### 候选 %d\n%s\n", candidate.Index+1, candidate.Content)
+	}
+	if valid == 0 {
+		return fmt.Errorf("没有生成成功的候选提示词")
+	}
+
+	reply, err := p.chatOnce(judge, []types.Message{
+		{Role: "system", Content: rankMetaPrompt},
+		{Role: "user", Content: sb.String()},
+	})
+	if err != nil {
+		return err
+	}
+
+	var verdict struct {
+		Ranking []struct {
+			Index  int     `json:"index"`
+			Score  float64 `json:"score"`
+			Reason string  `json:"reason"`
+		} `json:"ranking"`
+	}
+	if err := json.Unmarshal([]byte(extractJSONObject(reply)), &verdict); err != nil {
+		return fmt.Errorf("无法解析评审结果: %s", reply)
+	}
+
+	rank := 0
+	for _, item := range verdict.Ranking {
+		index := item.Index - 1 // 提示给评审模型的编号从 1 开始
+		if index < 0 || index >= len(candidates) || candidates[index].Rank != 0 {
+			continue
+		}
+		rank++
+		candidates[index].Rank = rank
+		candidates[index].Score = item.Score
+		candidates[index].Reason = item.Reason
+	}
+	return nil
+}
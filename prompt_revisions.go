@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"tools-ollama/types"
+)
+
+// promptRevisionsKeyPrefix 提示词历史版本的哈希键前缀, 每个提示词一个哈希, 字段为版本号
+const promptRevisionsKeyPrefix = "prompt_revisions:"
+
+// ListPromptRevisions 返回提示词的所有历史版本, 最新版本在前
+func (p *PromptEngineering) ListPromptRevisions(promptID string) ([]types.PromptRevision, error) {
+	dataMap, err := p.store.HGetAll(promptRevisionsKeyPrefix + promptID)
+	if err != nil {
+		p.logger.Error("获取提示词历史版本失败", "id", promptID, "error", err)
+		return nil, fmt.Errorf("获取提示词历史版本失败: %w", err)
+	}
+
+	revisions := make([]types.PromptRevision, 0, len(dataMap))
+	for _, data := range dataMap {
+		var revision types.PromptRevision
+		if err := json.Unmarshal([]byte(data), &revision); err != nil {
+			p.logger.Warn("解析提示词历史版本失败", "id", promptID, "error", err)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Version > revisions[j].Version })
+	return revisions, nil
+}
+
+// GetPromptRevision 获取提示词的指定历史版本
+func (p *PromptEngineering) GetPromptRevision(promptID string, version int) (types.PromptRevision, error) {
+	data, err := p.store.HGet(promptRevisionsKeyPrefix+promptID, strconv.Itoa(version))
+	if err != nil || data == "" {
+		return types.PromptRevision{}, fmt.Errorf("提示词版本不存在: %d", version)
+	}
+	var revision types.PromptRevision
+	if err := json.Unmarshal([]byte(data), &revision); err != nil {
+		p.logger.Error("解析提示词历史版本失败", "id", promptID, "version", version, "error", err)
+		return types.PromptRevision{}, err
+	}
+	return revision, nil
+}
+
+// DiffPromptRevisions 比较提示词的两个历史版本
+func (p *PromptEngineering) DiffPromptRevisions(promptID string, fromVersion int, toVersion int) (types.PromptRevisionDiff, error) {
+	from, err := p.GetPromptRevision(promptID, fromVersion)
+	if err != nil {
+		return types.PromptRevisionDiff{}, err
+	}
+	to, err := p.GetPromptRevision(promptID, toVersion)
+	if err != nil {
+		return types.PromptRevisionDiff{}, err
+	}
+
+	return types.PromptRevisionDiff{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Content:     DiffText(from.Prompt.Content, to.Prompt.Content),
+		Changes:     promptMetadataChanges(from.Prompt, to.Prompt),
+	}, nil
+}
+
+// RollbackPrompt 以指定历史版本的内容创建一个新版本, 不会删除中间的版本
+func (p *PromptEngineering) RollbackPrompt(promptID string, version int) (types.Prompt, error) {
+	p.logger.Info("回滚提示词", "id", promptID, "version", version)
+	if _, err := p.GetPrompt(promptID); err != nil {
+		return types.Prompt{}, fmt.Errorf("提示词不存在: %s", promptID)
+	}
+	revision, err := p.GetPromptRevision(promptID, version)
+	if err != nil {
+		return types.Prompt{}, err
+	}
+
+	prompt := revision.Prompt
+	prompt.ID = promptID
+	return p.savePrompt(prompt, fmt.Sprintf("回滚自版本 %d", version))
+}
+
+// saveRevision 记录提示词的一个版本, 已存在的版本不会被覆盖
+func (p *PromptEngineering) saveRevision(prompt types.Prompt, note string) error {
+	key := promptRevisionsKeyPrefix + prompt.ID
+	field := strconv.Itoa(prompt.Version)
+	if existing, err := p.store.HGet(key, field); err == nil && existing != "" {
+		p.logger.Warn("提示词版本已存在, 跳过记录", "id", prompt.ID, "version", prompt.Version)
+		return nil
+	}
+
+	revision := types.PromptRevision{
+		PromptID:  prompt.ID,
+		Version:   prompt.Version,
+		Prompt:    prompt,
+		Author:    prompt.CreatedBy,
+		Note:      note,
+		Timestamp: prompt.UpdatedAt,
+	}
+	data, err := json.Marshal(revision)
+	if err != nil {
+		p.logger.Error("序列化提示词历史版本失败", "error", err)
+		return err
+	}
+	if err := p.store.HSet(key, field, string(data)); err != nil {
+		p.logger.Error("保存提示词历史版本失败", "id", prompt.ID, "version", prompt.Version, "error", err)
+		return fmt.Errorf("保存提示词历史版本失败: %w", err)
+	}
+	return nil
+}
+
+// ensureRevision 确保提示词的当前版本已有历史记录
+func (p *PromptEngineering) ensureRevision(prompt types.Prompt) error {
+	if _, err := p.GetPromptRevision(prompt.ID, prompt.Version); err == nil {
+		return nil
+	}
+	return p.saveRevision(prompt, "")
+}
+
+// deleteRevisions 删除提示词的所有历史版本
+func (p *PromptEngineering) deleteRevisions(promptID string) {
+	key := promptRevisionsKeyPrefix + promptID
+	dataMap, err := p.store.HGetAll(key)
+	if err != nil {
+		p.logger.Warn("获取提示词历史版本失败", "id", promptID, "error", err)
+		return
+	}
+	for field := range dataMap {
+		if err := p.store.HDel(key, field); err != nil {
+			p.logger.Warn("删除提示词历史版本失败", "id", promptID, "version", field, "error", err)
+		}
+	}
+}
+
+// promptMetadataChanges 返回两个版本之间内容以外发生变化的字段
+func promptMetadataChanges(from types.Prompt, to types.Prompt) []string {
+	changes := []string{}
+	if from.Name != to.Name {
+		changes = append(changes, "name")
+	}
+	if from.Description != to.Description {
+		changes = append(changes, "description")
+	}
+	if !reflect.DeepEqual(from.Tags, to.Tags) {
+		changes = append(changes, "tags")
+	}
+	if !reflect.DeepEqual(from.Models, to.Models) {
+		changes = append(changes, "models")
+	}
+	if !reflect.DeepEqual(from.Variables, to.Variables) {
+		changes = append(changes, "variables")
+	}
+	if from.CreatedBy != to.CreatedBy {
+		changes = append(changes, "createdBy")
+	}
+	return changes
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+)
+
+// QueryPrompts 按条件搜索、过滤和排序提示词, 同时返回标签、模型和创建者的分面统计
+func (p *PromptEngineering) QueryPrompts(query types.PromptQuery) (types.PromptQueryResult, error) {
+	p.logger.Debug("查询提示词", "text", query.Text, "tags", query.Tags, "models", query.Models, "createdBy", query.CreatedBy, "sortBy", query.SortBy)
+	if query.Offset < 0 || query.Limit < 0 {
+		return types.PromptQueryResult{}, fmt.Errorf("分页参数不能为负数")
+	}
+
+	prompts, err := p.ListPrompts()
+	if err != nil {
+		return types.PromptQueryResult{}, err
+	}
+
+	terms := strings.Fields(strings.ToLower(query.Text))
+	scores := make(map[string]int, len(prompts))
+	matched := make([]types.Prompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		if !promptHasAllTags(prompt, query.Tags) || !promptHasAnyModel(prompt, query.Models) {
+			continue
+		}
+		if query.CreatedBy != "" && !strings.EqualFold(prompt.CreatedBy, query.CreatedBy) {
+			continue
+		}
+		score, ok := promptTextScore(prompt, terms)
+		if !ok {
+			continue
+		}
+		scores[prompt.ID] = score
+		matched = append(matched, prompt)
+	}
+
+	if err := p.sortPrompts(matched, query, scores); err != nil {
+		return types.PromptQueryResult{}, err
+	}
+
+	result := types.PromptQueryResult{
+		Total:    len(matched),
+		Tags:     promptFacets(matched, func(prompt types.Prompt) []string { return prompt.Tags }),
+		Models:   promptFacets(matched, func(prompt types.Prompt) []string { return prompt.Models }),
+		Creators: promptFacets(matched, func(prompt types.Prompt) []string { return []string{prompt.CreatedBy} }),
+	}
+
+	start := query.Offset
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := len(matched)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+	result.Prompts = matched[start:end]
+	return result, nil
+}
+
+// ListPromptTags 返回所有提示词的标签及其数量, 供分面侧边栏初始化
+func (p *PromptEngineering) ListPromptTags() ([]types.PromptFacet, error) {
+	prompts, err := p.ListPrompts()
+	if err != nil {
+		return nil, err
+	}
+	return promptFacets(prompts, func(prompt types.Prompt) []string { return prompt.Tags }), nil
+}
+
+// sortPrompts 按查询条件排序, 相同时按名称排序以保证结果稳定
+func (p *PromptEngineering) sortPrompts(prompts []types.Prompt, query types.PromptQuery, scores map[string]int) error {
+	sortBy := query.SortBy
+	if sortBy == "" {
+		sortBy = "updatedAt"
+		if strings.TrimSpace(query.Text) != "" {
+			sortBy = "relevance"
+		}
+	}
+
+	var usage map[string]int
+	var less func(a, b types.Prompt) bool
+	switch sortBy {
+	case "updatedAt":
+		less = func(a, b types.Prompt) bool { return a.UpdatedAt < b.UpdatedAt }
+	case "createdAt":
+		less = func(a, b types.Prompt) bool { return a.CreatedAt < b.CreatedAt }
+	case "name":
+		less = func(a, b types.Prompt) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "usage":
+		usage = p.promptUsageCounts()
+		less = func(a, b types.Prompt) bool { return usage[a.ID] < usage[b.ID] }
+	case "relevance":
+		less = func(a, b types.Prompt) bool { return scores[a.ID] < scores[b.ID] }
+	default:
+		return fmt.Errorf("不支持的排序字段: %s", query.SortBy)
+	}
+
+	desc := sortBy != "name"
+	switch query.SortOrder {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		return fmt.Errorf("不支持的排序方向: %s", query.SortOrder)
+	}
+
+	sort.SliceStable(prompts, func(i, j int) bool {
+		a, b := prompts[i], prompts[j]
+		if less(a, b) != less(b, a) {
+			return less(a, b) != desc
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return nil
+}
+
+// promptUsageCounts 读取各提示词的使用次数, 读取失败时视为没有使用记录
+func (p *PromptEngineering) promptUsageCounts() map[string]int {
+	dataMap, err := p.store.HGetAll(promptUsageCountsKey)
+	if err != nil {
+		p.logger.Warn("获取提示词使用次数失败", "error", err)
+		return nil
+	}
+	counts := make(map[string]int, len(dataMap))
+	for id, value := range dataMap {
+		if n, err := strconv.Atoi(value); err == nil {
+			counts[id] = n
+		}
+	}
+	return counts
+}
+
+// promptTextScore 判断提示词是否包含全部搜索词并计算相关度, 名称中的匹配权重最高
+func promptTextScore(prompt types.Prompt, terms []string) (int, bool) {
+	name := strings.ToLower(prompt.Name)
+	description := strings.ToLower(prompt.Description)
+	content := strings.ToLower(prompt.Content)
+
+	score := 0
+	for _, term := range terms {
+		termScore := 0
+		if strings.Contains(name, term) {
+			termScore += 5
+			if name == term {
+				termScore += 5
+			}
+		}
+		if strings.Contains(description, term) {
+			termScore += 2
+		}
+		if n := strings.Count(content, term); n > 0 {
+			termScore += min(n, 5)
+		}
+		if termScore == 0 {
+			return 0, false
+		}
+		score += termScore
+	}
+	return score, true
+}
+
+func promptHasAllTags(prompt types.Prompt, tags []string) bool {
+	for _, tag := range tags {
+		if !containsFold(prompt.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func promptHasAnyModel(prompt types.Prompt, models []string) bool {
+	if len(models) == 0 {
+		return true
+	}
+	for _, model := range models {
+		if containsFold(prompt.Models, model) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// promptFacets 统计每个取值出现的提示词数量, 按数量降序、取值升序排列, 忽略空值
+func promptFacets(prompts []types.Prompt, values func(types.Prompt) []string) []types.PromptFacet {
+	counts := make(map[string]int)
+	for _, prompt := range prompts {
+		seen := make(map[string]bool)
+		for _, value := range values(prompt) {
+			value = strings.TrimSpace(value)
+			if value == "" || seen[value] {
+				continue
+			}
+			seen[value] = true
+			counts[value]++
+		}
+	}
+
+	facets := make([]types.PromptFacet, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, types.PromptFacet{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
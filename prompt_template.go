@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+)
+
+var (
+	// promptPlaceholder 匹配提示词中的 {{name}} 占位符
+	promptPlaceholder  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	promptVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// PromptPlaceholders 按首次出现的顺序返回内容中的占位符名称
+func PromptPlaceholders(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range promptPlaceholder.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// ResolvePromptVariables 合并变量定义与内容中的占位符, 按占位符出现顺序返回
+// 没有定义的占位符视为必填的单行文本变量, 内容中未使用的定义会被忽略
+func ResolvePromptVariables(prompt types.Prompt) []types.PromptVariable {
+	defs := make(map[string]types.PromptVariable, len(prompt.Variables))
+	for _, v := range prompt.Variables {
+		defs[v.Name] = v
+	}
+
+	placeholders := PromptPlaceholders(prompt.Content)
+	vars := make([]types.PromptVariable, 0, len(placeholders))
+	for _, name := range placeholders {
+		v, ok := defs[name]
+		if !ok {
+			v = types.PromptVariable{Name: name, Required: true}
+		}
+		if v.Type == "" {
+			v.Type = types.PromptVariableString
+		}
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// ValidatePromptVariables 校验变量定义: 名称合法且唯一、类型已知、枚举有可选值、默认值符合类型
+func ValidatePromptVariables(vars []types.PromptVariable) error {
+	seen := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		if !promptVariableName.MatchString(v.Name) {
+			return fmt.Errorf("变量名 %q 无效, 只能包含字母、数字和下划线且不能以数字开头", v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("变量 %s 重复定义", v.Name)
+		}
+		seen[v.Name] = true
+
+		switch v.Type {
+		case "", types.PromptVariableString, types.PromptVariableText, types.PromptVariableNumber, types.PromptVariableBoolean:
+		case types.PromptVariableEnum:
+			if len(v.Options) == 0 {
+				return fmt.Errorf("枚举变量 %s 至少需要一个可选值", v.Name)
+			}
+		default:
+			return fmt.Errorf("变量 %s 的类型 %q 无效", v.Name, v.Type)
+		}
+
+		if v.Default != "" {
+			if err := checkPromptValue(v, v.Default); err != nil {
+				return fmt.Errorf("变量 %s 的默认值无效: %w", v.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RenderPromptTemplate 校验变量取值并渲染提示词内容
+// 未提供的变量使用默认值; 所有校验错误会一并返回, 方便表单一次性提示
+func RenderPromptTemplate(prompt types.Prompt, values map[string]interface{}) (string, error) {
+	vars := ResolvePromptVariables(prompt)
+	resolved := make(map[string]string, len(vars))
+	var problems []string
+
+	for _, v := range vars {
+		value := formatPromptValue(values[v.Name])
+		if value == "" {
+			value = v.Default
+		}
+		if value == "" {
+			if v.Required {
+				problems = append(problems, fmt.Sprintf("缺少必填变量 %s", v.Name))
+			}
+			continue
+		}
+		if err := checkPromptValue(v, value); err != nil {
+			problems = append(problems, fmt.Sprintf("变量 %s: %v", v.Name, err))
+			continue
+		}
+		resolved[v.Name] = value
+	}
+	if len(problems) > 0 {
+		return "", fmt.Errorf("提示词变量校验失败: %s", strings.Join(problems, "; "))
+	}
+
+	return promptPlaceholder.ReplaceAllStringFunc(prompt.Content, func(match string) string {
+		name := promptPlaceholder.FindStringSubmatch(match)[1]
+		return resolved[name]
+	}), nil
+}
+
+// checkPromptValue 检查取值是否符合变量类型
+func checkPromptValue(v types.PromptVariable, value string) error {
+	switch v.Type {
+	case "", types.PromptVariableString:
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("单行文本不能包含换行")
+		}
+	case types.PromptVariableNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q 不是有效的数字", value)
+		}
+	case types.PromptVariableBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("只能是 true 或 false")
+		}
+	case types.PromptVariableEnum:
+		for _, option := range v.Options {
+			if option == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q 不在可选值 %v 中", value, v.Options)
+	}
+	return nil
+}
+
+// formatPromptValue 将前端或 API 传入的值转换为文本
+func formatPromptValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"tools-ollama/types"
+)
+
+const (
+	// promptUsageKeyPrefix 提示词使用关联的哈希键前缀, 每个提示词一个哈希
+	promptUsageKeyPrefix = "prompt_usage:"
+	// promptUsageCountsKey 提示词使用次数的哈希键, 字段为提示词ID, 值为请求次数, 用于按使用量排序
+	promptUsageCountsKey = "prompt_usage_counts"
+	// promptUsageRecentLimit 使用统计中返回的最近关联数量
+	promptUsageRecentLimit = 20
+)
+
+// PromptUsageRecorder 记录提示词的使用情况, 由 PromptEngineering 实现
+type PromptUsageRecorder interface {
+	RecordPromptUsage(usage types.PromptUsage)
+}
+
+// RecordPromptUsage 记录一次提示词使用, 失败只记录日志, 不影响调用方
+func (p *PromptEngineering) RecordPromptUsage(usage types.PromptUsage) {
+	if usage.PromptID == "" {
+		return
+	}
+	if err := p.recordPromptUsage(usage); err != nil {
+		p.logger.Warn("记录提示词使用失败", "promptId", usage.PromptID, "source", usage.Source, "error", err)
+	}
+}
+
+func (p *PromptEngineering) recordPromptUsage(usage types.PromptUsage) error {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+
+	key := promptUsageKeyPrefix + usage.PromptID
+	field := strings.Join([]string{usage.Source, usage.ConversationID, strconv.Itoa(usage.Version), usage.Model}, "|")
+	now := GetCurrentTimestamp()
+
+	record := usage
+	record.Count = 0
+	record.FirstUsedAt = now
+	if data, err := p.store.HGet(key, field); err == nil && data != "" {
+		var existing types.PromptUsage
+		if err := json.Unmarshal([]byte(data), &existing); err == nil {
+			record.Count = existing.Count
+			record.FirstUsedAt = existing.FirstUsedAt
+		}
+	}
+	record.Count++
+	record.LastUsedAt = now
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := p.store.HSet(key, field, string(data)); err != nil {
+		return fmt.Errorf("保存提示词使用记录失败: %w", err)
+	}
+
+	total := 0
+	if value, err := p.store.HGet(promptUsageCountsKey, usage.PromptID); err == nil {
+		total, _ = strconv.Atoi(value)
+	}
+	if err := p.store.HSet(promptUsageCountsKey, usage.PromptID, strconv.Itoa(total+1)); err != nil {
+		return fmt.Errorf("更新提示词使用次数失败: %w", err)
+	}
+	return nil
+}
+
+// GetPromptUsage 获取提示词的使用统计, 包括各版本和模型的使用次数以及最近使用的对话
+func (p *PromptEngineering) GetPromptUsage(promptID string) (types.PromptUsageStats, error) {
+	usages, err := p.listPromptUsage(promptID)
+	if err != nil {
+		return types.PromptUsageStats{}, err
+	}
+
+	stats := types.PromptUsageStats{PromptID: promptID, Versions: []types.PromptVersionUsage{}, Models: []types.PromptFacet{}}
+	versions := make(map[int]int)
+	models := make(map[string]int)
+	conversations := make(map[string]bool)
+	for _, usage := range usages {
+		stats.TotalCount += usage.Count
+		stats.LastUsedAt = max(stats.LastUsedAt, usage.LastUsedAt)
+		versions[usage.Version] += usage.Count
+		if usage.Model != "" {
+			models[usage.Model] += usage.Count
+		}
+		if usage.ConversationID != "" {
+			conversations[usage.ConversationID] = true
+		}
+	}
+	stats.ConversationCount = len(conversations)
+
+	for version, count := range versions {
+		stats.Versions = append(stats.Versions, types.PromptVersionUsage{Version: version, Count: count})
+	}
+	sort.Slice(stats.Versions, func(i, j int) bool { return stats.Versions[i].Version > stats.Versions[j].Version })
+	for model, count := range models {
+		stats.Models = append(stats.Models, types.PromptFacet{Value: model, Count: count})
+	}
+	sort.Slice(stats.Models, func(i, j int) bool {
+		if stats.Models[i].Count != stats.Models[j].Count {
+			return stats.Models[i].Count > stats.Models[j].Count
+		}
+		return stats.Models[i].Value < stats.Models[j].Value
+	})
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].LastUsedAt > usages[j].LastUsedAt })
+	if len(usages) > promptUsageRecentLimit {
+		usages = usages[:promptUsageRecentLimit]
+	}
+	stats.Recent = usages
+	return stats, nil
+}
+
+// listPromptUsage 读取提示词的全部使用关联
+func (p *PromptEngineering) listPromptUsage(promptID string) ([]types.PromptUsage, error) {
+	dataMap, err := p.store.HGetAll(promptUsageKeyPrefix + promptID)
+	if err != nil {
+		p.logger.Error("获取提示词使用记录失败", "id", promptID, "error", err)
+		return nil, fmt.Errorf("获取提示词使用记录失败: %w", err)
+	}
+	usages := make([]types.PromptUsage, 0, len(dataMap))
+	for _, data := range dataMap {
+		var usage types.PromptUsage
+		if err := json.Unmarshal([]byte(data), &usage); err != nil {
+			p.logger.Warn("解析提示词使用记录失败", "id", promptID, "error", err)
+			continue
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// deleteUsage 删除提示词的使用记录
+func (p *PromptEngineering) deleteUsage(promptID string) {
+	p.usageMu.Lock()
+	defer p.usageMu.Unlock()
+
+	key := promptUsageKeyPrefix + promptID
+	dataMap, err := p.store.HGetAll(key)
+	if err != nil {
+		p.logger.Warn("获取提示词使用记录失败", "id", promptID, "error", err)
+		return
+	}
+	for field := range dataMap {
+		if err := p.store.HDel(key, field); err != nil {
+			p.logger.Warn("删除提示词使用记录失败", "id", promptID, "error", err)
+		}
+	}
+	if err := p.store.HDel(promptUsageCountsKey, promptID); err != nil {
+		p.logger.Warn("删除提示词使用次数失败", "id", promptID, "error", err)
+	}
+}
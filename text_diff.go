@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"tools-ollama/types"
+)
+
+// DiffText 基于最长公共子序列计算两段文本的逐行差异
+func DiffText(original string, updated string) types.TextDiff {
+	a := splitDiffLines(original)
+	b := splitDiffLines(updated)
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := types.TextDiff{Lines: make([]types.DiffLine, 0, max(len(a), len(b)))}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff.Lines = append(diff.Lines, types.DiffLine{Type: "equal", Text: a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			diff.Lines = append(diff.Lines, types.DiffLine{Type: "insert", Text: b[j]})
+			diff.Added++
+			j++
+		default:
+			diff.Lines = append(diff.Lines, types.DiffLine{Type: "delete", Text: a[i]})
+			diff.Removed++
+			i++
+		}
+	}
+	return diff
+}
+
+// splitDiffLines 按行拆分文本, 空文本返回空切片
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// ThinkTagSplitter 从流式输出中拆分 <think>...</think> 包裹的思考过程
+// 用于不支持 think 参数、把推理内容直接写在 content 里的模型
+// 只识别回复开头的 <think> 标签, 避免误伤正文中讨论标签本身的内容
+type ThinkTagSplitter struct {
+	prefilled bool // 模型模板已预填 <think>, 回复直接从思考过程开始
+	started   bool // 已经收到过非空白文本
+	inThink   bool
+	done      bool   // 已经输出了正文, 之后不再识别标签
+	pending   string // 可能是标签前缀、暂未确定归属的文本
+}
+
+// NewThinkTagSplitter 创建拆分器
+// prefilled 为 true 时表示模型模板在回复前预填了 <think>, 模型只会输出结束标签 </think>
+func NewThinkTagSplitter(prefilled bool) *ThinkTagSplitter {
+	return &ThinkTagSplitter{prefilled: prefilled, inThink: prefilled}
+}
+
+// Feed 输入一段新文本, 返回其中可以确定归属的思考内容和正文
+func (s *ThinkTagSplitter) Feed(text string) (thinking string, content string) {
+	if s.done {
+		return "", text
+	}
+
+	buf := s.pending + text
+	s.pending = ""
+	var thinkBuf, contentBuf strings.Builder
+
+	for buf != "" {
+		if !s.started {
+			trimmed := strings.TrimLeftFunc(buf, unicode.IsSpace)
+			if trimmed == "" || (s.prefilled && strings.HasPrefix(thinkOpenTag, trimmed)) {
+				s.pending = buf
+				buf = ""
+				continue
+			}
+			s.started = true
+			// 预填模板下模型偶尔仍会重复输出开始标签, 直接跳过
+			if s.prefilled && strings.HasPrefix(trimmed, thinkOpenTag) {
+				buf = trimmed[len(thinkOpenTag):]
+				continue
+			}
+		}
+
+		if !s.inThink {
+			trimmed := strings.TrimLeftFunc(buf, unicode.IsSpace)
+			switch {
+			case strings.HasPrefix(trimmed, thinkOpenTag):
+				s.inThink = true
+				buf = trimmed[len(thinkOpenTag):]
+			case trimmed == "" || strings.HasPrefix(thinkOpenTag, trimmed):
+				// 还不能确定是否为开始标签
+				s.pending = buf
+				buf = ""
+			default:
+				s.done = true
+				contentBuf.WriteString(trimmed)
+				buf = ""
+			}
+			continue
+		}
+
+		if idx := strings.Index(buf, thinkCloseTag); idx >= 0 {
+			thinkBuf.WriteString(buf[:idx])
+			buf = strings.TrimLeftFunc(buf[idx+len(thinkCloseTag):], unicode.IsSpace)
+			s.inThink = false
+			s.done = buf != ""
+			contentBuf.WriteString(buf)
+			buf = ""
+			continue
+		}
+
+		keep := partialTagSuffix(buf, thinkCloseTag)
+		thinkBuf.WriteString(buf[:len(buf)-keep])
+		s.pending = buf[len(buf)-keep:]
+		buf = ""
+	}
+
+	return thinkBuf.String(), contentBuf.String()
+}
+
+// Flush 在流结束时返回剩余的未决文本
+func (s *ThinkTagSplitter) Flush() (thinking string, content string) {
+	rest := s.pending
+	s.pending = ""
+	if s.inThink {
+		return rest, ""
+	}
+	return "", strings.TrimLeftFunc(rest, unicode.IsSpace)
+}
+
+// SplitThinkTags 拆分完整文本中的思考过程和正文, 只识别回复开头的 <think> 标签
+func SplitThinkTags(text string) (thinking string, content string) {
+	return NewThinkTagSplitter(false).Split(text)
+}
+
+// Split 一次性拆分完整文本
+func (s *ThinkTagSplitter) Split(text string) (thinking string, content string) {
+	thinking, content = s.Feed(text)
+	restThinking, restContent := s.Flush()
+	return strings.TrimSpace(thinking + restThinking), content + restContent
+}
+
+// chatStreamSplitter 拆分流式聊天响应块中的思考过程和正文
+// 模型通过 thinking 字段返回思考过程 (请求设置了 think, 或响应中出现了 thinking) 时, content 就是正文, 不再解析 <think> 标签
+type chatStreamSplitter struct {
+	tags   *ThinkTagSplitter
+	native bool
+}
+
+// newChatStreamSplitter prefilled 为模型模板是否预填了 <think>, native 为请求是否显式设置了 think
+func newChatStreamSplitter(prefilled bool, native bool) *chatStreamSplitter {
+	return &chatStreamSplitter{tags: NewThinkTagSplitter(prefilled), native: native}
+}
+
+// Feed 输入一个响应块的 thinking 和 content 字段, 返回拆分后的思考过程和正文
+func (s *chatStreamSplitter) Feed(thinking string, content string) (string, string) {
+	if thinking != "" && !s.native {
+		s.native = true
+		// 之前暂存在标签拆分器中的文本按原归属输出
+		restThinking, restContent := s.tags.Flush()
+		return restThinking + thinking, restContent + content
+	}
+	if s.native {
+		return thinking, content
+	}
+	return s.tags.Feed(content)
+}
+
+// Flush 在流结束时返回剩余的未决文本
+func (s *chatStreamSplitter) Flush() (string, string) {
+	if s.native {
+		return "", ""
+	}
+	return s.tags.Flush()
+}
+
+// partialTagSuffix 返回 text 末尾与 tag 前缀重合的最大长度
+func partialTagSuffix(text string, tag string) int {
+	for n := min(len(text), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(text, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestChatStreamSplitter(t *testing.T) {
+	type chunk struct{ thinking, content string }
+	tests := []struct {
+		name      string
+		prefilled bool
+		native    bool
+		chunks    []chunk
+		thinking  string
+		content   string
+	}{
+		{
+			name:     "think tags",
+			chunks:   []chunk{{"", "<thi"}, {"", "nk>plan</th"}, {"", "ink>\n\nanswer"}},
+			thinking: "plan",
+			content:  "answer",
+		},
+		{
+			name:      "prefilled template",
+			prefilled: true,
+			chunks:    []chunk{{"", "plan"}, {"", "</think>answer"}},
+			thinking:  "plan",
+			content:   "answer",
+		},
+		{
+			name:      "prefilled template with native thinking",
+			prefilled: true,
+			chunks:    []chunk{{"plan", ""}, {"more", ""}, {"", "answer"}, {"", " done"}},
+			thinking:  "planmore",
+			content:   "answer done",
+		},
+		{
+			name:      "native thinking after pending whitespace",
+			prefilled: true,
+			chunks:    []chunk{{"", "\n"}, {"plan", ""}, {"", "answer"}},
+			thinking:  "\nplan",
+			content:   "answer",
+		},
+		{
+			name:     "think requested without thinking output",
+			native:   true,
+			chunks:   []chunk{{"", "<think>literal</think>"}},
+			thinking: "",
+			content:  "<think>literal</think>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := newChatStreamSplitter(tt.prefilled, tt.native)
+			var thinking, content string
+			for _, c := range tt.chunks {
+				chunkThinking, chunkContent := splitter.Feed(c.thinking, c.content)
+				thinking += chunkThinking
+				content += chunkContent
+			}
+			restThinking, restContent := splitter.Flush()
+			thinking += restThinking
+			content += restContent
+			if thinking != tt.thinking || content != tt.content {
+				t.Errorf("got (%q, %q), want (%q, %q)", thinking, content, tt.thinking, tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,763 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tools-ollama/types"
+
+	"github.com/16chusi/duolasdk"
+	"github.com/16chusi/duolasdk/core"
+)
+
+const (
+	toolsKey        = "tools"
+	toolSettingsKey = "tool_settings"
+
+	// toolOutputLimit 工具返回给模型的内容上限, 避免撑爆上下文
+	toolOutputLimit = 256 << 10
+	// toolHTTPTimeout 工具发起 HTTP 请求的超时时间
+	toolHTTPTimeout = 15 * time.Second
+)
+
+// toolNamePattern 工具名称需要满足函数名的命名规则
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]{0,63}$`)
+
+// ToolRegistry 管理内置工具和用户自定义工具, 并负责执行模型发起的工具调用
+type ToolRegistry struct {
+	store  *duolasdk.AppStore
+	logger *core.AppLog
+}
+
+// NewToolRegistry 创建工具注册表
+func NewToolRegistry(store *duolasdk.AppStore, logger *core.AppLog) *ToolRegistry {
+	return &ToolRegistry{
+		store:  store,
+		logger: logger.WithPrefix("ToolRegistry"),
+	}
+}
+
+// builtinTools 返回所有内置工具的定义
+func builtinTools() []types.ToolDefinition {
+	return []types.ToolDefinition{
+		{
+			Name:        "calculator",
+			Description: "Evaluate an arithmetic expression. Supports + - * / % ^, parentheses, constants pi and e, and functions sqrt, abs, sin, cos, tan, log, ln, exp, floor, ceil, round, min, max, pow.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"expression": map[string]interface{}{"type": "string", "description": "The expression to evaluate, e.g. (2 + 3) * sqrt(16)"},
+				},
+				"required": []string{"expression"},
+			},
+		},
+		{
+			Name:        "current_time",
+			Description: "Get the current date and time, optionally in a given IANA time zone.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"timezone": map[string]interface{}{"type": "string", "description": "IANA time zone name such as Asia/Shanghai. Defaults to local time."},
+				},
+			},
+		},
+		{
+			Name:        "read_file",
+			Description: "Read a text file from the sandbox directory. Paths are relative to the sandbox root.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{"type": "string", "description": "Relative path of the file inside the sandbox"},
+				},
+				"required": []string{"path"},
+			},
+		},
+		{
+			Name:        "http_get",
+			Description: "Fetch a URL with HTTP GET and return the response body. Only URLs on the configured allow-list can be fetched.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{"type": "string", "description": "The absolute http(s) URL to fetch"},
+				},
+				"required": []string{"url"},
+			},
+		},
+	}
+}
+
+// GetSettings 获取工具设置, 未保存时返回默认值
+func (r *ToolRegistry) GetSettings() types.ToolSettings {
+	settings := types.ToolSettings{
+		SandboxDir:    filepath.Join("data", "tool_sandbox"),
+		HTTPAllowList: []string{},
+		MaxToolRounds: 5,
+	}
+
+	data, err := r.store.Get(toolSettingsKey)
+	if err != nil || data == "" {
+		return settings
+	}
+	if err := UnmarshalJSONWithError([]byte(data), &settings, r.logger, "解析工具设置"); err != nil {
+		return settings
+	}
+	if settings.MaxToolRounds <= 0 {
+		settings.MaxToolRounds = 5
+	}
+	return settings
+}
+
+// SaveSettings 保存工具设置
+func (r *ToolRegistry) SaveSettings(settings types.ToolSettings) error {
+	if settings.SandboxDir == "" {
+		return fmt.Errorf("沙箱目录不能为空")
+	}
+	if _, err := parseURLAllowList(settings.HTTPAllowList); err != nil {
+		return err
+	}
+
+	data, err := MarshalJSONWithError(settings, r.logger, "序列化工具设置")
+	if err != nil {
+		return err
+	}
+	if err := r.store.Set(toolSettingsKey, string(data)); err != nil {
+		r.logger.Error("保存工具设置失败", "error", err)
+		return fmt.Errorf("保存工具设置失败: %w", err)
+	}
+	r.logger.Info("工具设置已保存", "sandboxDir", settings.SandboxDir, "allowListSize", len(settings.HTTPAllowList))
+	return nil
+}
+
+// ListTools 返回内置工具和用户自定义工具, 内置工具在前
+func (r *ToolRegistry) ListTools() ([]types.ToolDefinition, error) {
+	tools := builtinTools()
+	for i := range tools {
+		tools[i].Builtin = true
+		tools[i].Enabled = true
+	}
+
+	dataMap, err := r.store.HGetAll(toolsKey)
+	if err != nil {
+		r.logger.Error("获取自定义工具失败", "error", err)
+		return nil, fmt.Errorf("获取自定义工具失败: %w", err)
+	}
+
+	custom := make([]types.ToolDefinition, 0, len(dataMap))
+	for _, data := range dataMap {
+		var tool types.ToolDefinition
+		if err := UnmarshalJSONWithError([]byte(data), &tool, r.logger, "解析自定义工具"); err != nil {
+			continue
+		}
+		tool.SideEffect = true
+		custom = append(custom, tool)
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+
+	return append(tools, custom...), nil
+}
+
+// SaveTool 创建或更新一个用户自定义工具
+func (r *ToolRegistry) SaveTool(tool types.ToolDefinition) error {
+	if !toolNamePattern.MatchString(tool.Name) {
+		return fmt.Errorf("工具名称无效: 只能包含字母、数字、下划线和连字符, 且不能以数字开头")
+	}
+	for _, builtin := range builtinTools() {
+		if builtin.Name == tool.Name {
+			return fmt.Errorf("工具名称 %s 与内置工具冲突", tool.Name)
+		}
+	}
+	if tool.Parameters == nil {
+		tool.Parameters = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	if t, _ := tool.Parameters["type"].(string); t != "object" {
+		return fmt.Errorf("工具参数的 JSON Schema 顶层类型必须为 object")
+	}
+	if u, err := url.Parse(tool.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("工具地址无效: %s", tool.Endpoint)
+	}
+	tool.Builtin = false
+	// 自定义工具会把模型生成的参数发送到任意地址, 执行前始终需要用户确认
+	tool.SideEffect = true
+
+	data, err := MarshalJSONWithError(tool, r.logger, "序列化自定义工具")
+	if err != nil {
+		return err
+	}
+	if err := r.store.HSet(toolsKey, tool.Name, string(data)); err != nil {
+		r.logger.Error("保存自定义工具失败", "name", tool.Name, "error", err)
+		return fmt.Errorf("保存自定义工具失败: %w", err)
+	}
+	r.logger.Info("自定义工具已保存", "name", tool.Name)
+	return nil
+}
+
+// DeleteTool 删除一个用户自定义工具
+func (r *ToolRegistry) DeleteTool(name string) error {
+	if err := r.store.HDel(toolsKey, name); err != nil {
+		r.logger.Error("删除自定义工具失败", "name", name, "error", err)
+		return fmt.Errorf("删除自定义工具失败: %w", err)
+	}
+	r.logger.Info("自定义工具已删除", "name", name)
+	return nil
+}
+
+// Resolve 根据名称查找启用的工具定义
+func (r *ToolRegistry) Resolve(names []string) ([]types.ToolDefinition, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	all, err := r.ListTools()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]types.ToolDefinition, len(all))
+	for _, tool := range all {
+		byName[tool.Name] = tool
+	}
+
+	tools := make([]types.ToolDefinition, 0, len(names))
+	for _, name := range names {
+		tool, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("未找到工具: %s", name)
+		}
+		if !tool.Enabled {
+			continue
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// ToOllamaTools 将工具定义转换为 /api/chat 的 tools 字段
+func ToOllamaTools(tools []types.ToolDefinition) []OllamaTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	result := make([]OllamaTool, len(tools))
+	for i, tool := range tools {
+		result[i] = OllamaTool{
+			Type: "function",
+			Function: OllamaToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return result
+}
+
+// Execute 执行一次工具调用并返回交给模型的文本结果
+func (r *ToolRegistry) Execute(tool types.ToolDefinition, call types.ToolCall) (string, error) {
+	args := call.Function.Arguments
+	r.logger.Info("执行工具", "name", tool.Name, "arguments", args)
+
+	var output string
+	var err error
+	switch {
+	case !tool.Builtin:
+		output, err = r.callEndpoint(tool, args)
+	case tool.Name == "calculator":
+		output, err = toolCalculator(args)
+	case tool.Name == "current_time":
+		output, err = toolCurrentTime(args)
+	case tool.Name == "read_file":
+		output, err = toolReadFile(r.GetSettings().SandboxDir, args)
+	case tool.Name == "http_get":
+		output, err = toolHTTPGet(r.GetSettings().HTTPAllowList, args)
+	default:
+		err = fmt.Errorf("未实现的内置工具: %s", tool.Name)
+	}
+	if err != nil {
+		r.logger.Warn("工具执行失败", "name", tool.Name, "error", err)
+		return "", err
+	}
+
+	if len(output) > toolOutputLimit {
+		output = output[:toolOutputLimit] + "\n...[truncated]"
+	}
+	return output, nil
+}
+
+// callEndpoint 以 JSON 形式将参数 POST 到自定义工具的地址
+func (r *ToolRegistry) callEndpoint(tool types.ToolDefinition, args map[string]interface{}) (string, error) {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("序列化工具参数失败: %w", err)
+	}
+
+	client := CreateHTTPClientWithTimeout(toolHTTPTimeout)
+	resp, err := client.Post(tool.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("调用工具地址失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, toolOutputLimit+1))
+	if err != nil {
+		return "", fmt.Errorf("读取工具响应失败: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("工具返回错误状态 %d: %s", resp.StatusCode, string(respBody))
+	}
+	return string(respBody), nil
+}
+
+// argString 从工具参数中读取字符串字段
+func argString(args map[string]interface{}, key string) string {
+	switch v := args[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// toolCurrentTime 返回当前时间
+func toolCurrentTime(args map[string]interface{}) (string, error) {
+	now := time.Now()
+	if tz := argString(args, "timezone"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", fmt.Errorf("未知的时区: %s", tz)
+		}
+		now = now.In(loc)
+	}
+	return now.Format("2006-01-02 15:04:05 Monday MST (-07:00)"), nil
+}
+
+// toolReadFile 读取沙箱目录中的文件, 拒绝访问沙箱以外的路径
+func toolReadFile(sandboxDir string, args map[string]interface{}) (string, error) {
+	rel := argString(args, "path")
+	if rel == "" {
+		return "", fmt.Errorf("缺少参数 path")
+	}
+
+	root, err := filepath.Abs(sandboxDir)
+	if err != nil {
+		return "", fmt.Errorf("解析沙箱目录失败: %w", err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", fmt.Errorf("创建沙箱目录失败: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	target := filepath.Join(root, filepath.Clean("/"+rel))
+	if resolved, err := filepath.EvalSymlinks(target); err == nil {
+		target = resolved
+	} else {
+		return "", fmt.Errorf("文件不存在: %s", rel)
+	}
+	if r, err := filepath.Rel(root, target); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("不允许访问沙箱以外的路径: %s", rel)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(target)
+		if err != nil {
+			return "", fmt.Errorf("读取目录失败: %w", err)
+		}
+		names := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() {
+				name += "/"
+			}
+			names = append(names, name)
+		}
+		return strings.Join(names, "\n"), nil
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, toolOutputLimit+1))
+	if err != nil {
+		return "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// toolHTTPGet 请求白名单中的 URL
+func toolHTTPGet(allowList []string, args map[string]interface{}) (string, error) {
+	rules, err := parseURLAllowList(allowList)
+	if err != nil {
+		return "", err
+	}
+	rawURL := argString(args, "url")
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("无效的URL: %s", rawURL)
+	}
+	if !urlAllowed(rules, u) {
+		return "", fmt.Errorf("URL 不在允许列表中: %s", rawURL)
+	}
+
+	client := CreateHTTPClientWithTimeout(toolHTTPTimeout)
+	// 禁止重定向到白名单以外的地址
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if urlAllowed(rules, req.URL) {
+			return nil
+		}
+		return fmt.Errorf("重定向目标不在允许列表中: %s", req.URL)
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return "", fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, toolOutputLimit+1))
+	if err != nil {
+		return "", fmt.Errorf("读取响应失败: %w", err)
+	}
+	return fmt.Sprintf("HTTP %d\n\n%s", resp.StatusCode, string(body)), nil
+}
+
+// urlAllowRule 解析后的 URL 白名单条目: 协议、主机和端口必须完全一致, 路径按 / 分段匹配前缀
+type urlAllowRule struct {
+	scheme string
+	host   string
+	port   string
+	path   string
+}
+
+// parseURLAllowList 解析 URL 白名单, 条目必须是不带用户信息的 http(s) 地址
+func parseURLAllowList(entries []string) ([]urlAllowRule, error) {
+	rules := make([]urlAllowRule, 0, len(entries))
+	for _, entry := range entries {
+		u, err := url.Parse(strings.TrimSpace(entry))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+			return nil, fmt.Errorf("无效的URL前缀: %s", entry)
+		}
+		rules = append(rules, urlAllowRule{
+			scheme: u.Scheme,
+			host:   strings.ToLower(u.Hostname()),
+			port:   effectivePort(u),
+			path:   strings.TrimRight(u.Path, "/"),
+		})
+	}
+	return rules, nil
+}
+
+// urlAllowed 判断 URL 是否命中白名单, 带用户信息的 URL 一律拒绝
+func urlAllowed(rules []urlAllowRule, u *url.URL) bool {
+	if u.User != nil {
+		return false
+	}
+	urlPath := path.Clean("/" + u.Path)
+	for _, rule := range rules {
+		if !strings.EqualFold(u.Scheme, rule.scheme) || strings.ToLower(u.Hostname()) != rule.host || effectivePort(u) != rule.port {
+			continue
+		}
+		if rule.path == "" || urlPath == rule.path || strings.HasPrefix(urlPath, rule.path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// effectivePort 返回 URL 的端口, 未指定时按协议取默认端口
+func effectivePort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+// toolCalculator 计算算术表达式
+func toolCalculator(args map[string]interface{}) (string, error) {
+	expr := argString(args, "expression")
+	if strings.TrimSpace(expr) == "" {
+		return "", fmt.Errorf("缺少参数 expression")
+	}
+	value, err := EvaluateExpression(expr)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64), nil
+}
+
+const (
+	// maxExpressionLength 计算器表达式的最大长度
+	maxExpressionLength = 1000
+	// maxExpressionDepth 括号、一元运算符和乘方的最大嵌套层数, 防止递归过深
+	maxExpressionDepth = 100
+)
+
+// EvaluateExpression 使用递归下降解析并计算算术表达式
+func EvaluateExpression(expr string) (float64, error) {
+	if len(expr) > maxExpressionLength {
+		return 0, fmt.Errorf("表达式过长, 最多 %d 个字符", maxExpressionLength)
+	}
+	p := &exprParser{input: expr}
+	value, err := p.parseExpr()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("表达式在位置 %d 处有多余的字符: %q", p.pos, p.input[p.pos:])
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("计算结果无效")
+	}
+	return value, nil
+}
+
+// exprParser 算术表达式解析器
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = ("+" | "-") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | ident [ "(" args ")" ] | "(" expr ")"
+type exprParser struct {
+	input string
+	pos   int
+	depth int // 当前嵌套层数
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parseExpr() (float64, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+}
+
+func (p *exprParser) parseTerm() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/':
+			if right == 0 {
+				return 0, fmt.Errorf("除数不能为零")
+			}
+			left /= right
+		case '%':
+			if right == 0 {
+				return 0, fmt.Errorf("除数不能为零")
+			}
+			left = math.Mod(left, right)
+		}
+	}
+}
+
+func (p *exprParser) parseUnary() (float64, error) {
+	// 所有递归 (括号、函数参数、一元运算符和乘方) 都会经过这里
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return 0, fmt.Errorf("表达式嵌套过深, 最多 %d 层", maxExpressionDepth)
+	}
+
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.parseUnary()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() == '^' {
+		p.pos++
+		exp, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exp), nil
+	}
+	return base, nil
+}
+
+func (p *exprParser) parsePrimary() (float64, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		v, err := p.parseExpr()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("缺少右括号")
+		}
+		p.pos++
+		return v, nil
+	case (c >= '0' && c <= '9') || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		// 科学计数法, 如 1e-3
+		if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+			p.pos++
+			if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+				p.pos++
+			}
+			for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+				p.pos++
+			}
+		}
+		v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的数字: %s", p.input[start:p.pos])
+		}
+		return v, nil
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z' || p.input[p.pos] >= 'A' && p.input[p.pos] <= 'Z' || p.input[p.pos] >= '0' && p.input[p.pos] <= '9') {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+		if p.peek() != '(' {
+			switch name {
+			case "pi":
+				return math.Pi, nil
+			case "e":
+				return math.E, nil
+			}
+			return 0, fmt.Errorf("未知的常量: %s", name)
+		}
+		p.pos++
+		var args []float64
+		if p.peek() != ')' {
+			for {
+				v, err := p.parseExpr()
+				if err != nil {
+					return 0, err
+				}
+				args = append(args, v)
+				if p.peek() != ',' {
+					break
+				}
+				p.pos++
+			}
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("函数 %s 缺少右括号", name)
+		}
+		p.pos++
+		return callMathFunc(name, args)
+	case c == 0:
+		return 0, fmt.Errorf("表达式不完整")
+	default:
+		return 0, fmt.Errorf("无法识别的字符: %q", c)
+	}
+}
+
+// callMathFunc 调用计算器支持的数学函数
+func callMathFunc(name string, args []float64) (float64, error) {
+	unary := map[string]func(float64) float64{
+		"sqrt": math.Sqrt, "abs": math.Abs, "sin": math.Sin, "cos": math.Cos, "tan": math.Tan,
+		"log": math.Log10, "ln": math.Log, "exp": math.Exp, "floor": math.Floor, "ceil": math.Ceil, "round": math.Round,
+	}
+	if fn, ok := unary[name]; ok {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("函数 %s 需要 1 个参数", name)
+		}
+		return fn(args[0]), nil
+	}
+
+	switch name {
+	case "pow":
+		if len(args) != 2 {
+			return 0, fmt.Errorf("函数 pow 需要 2 个参数")
+		}
+		return math.Pow(args[0], args[1]), nil
+	case "min", "max":
+		if len(args) == 0 {
+			return 0, fmt.Errorf("函数 %s 至少需要 1 个参数", name)
+		}
+		result := args[0]
+		for _, v := range args[1:] {
+			if name == "min" {
+				result = math.Min(result, v)
+			} else {
+				result = math.Max(result, v)
+			}
+		}
+		return result, nil
+	}
+	return 0, fmt.Errorf("未知的函数: %s", name)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestURLAllowed(t *testing.T) {
+	rules, err := parseURLAllowList([]string{"https://example.com/api/", "http://localhost:11434"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/api", true},
+		{"https://example.com/api/v1?q=1", true},
+		{"https://EXAMPLE.com:443/api/v1", true},
+		{"https://example.com/apix", false},
+		{"https://example.com/", false},
+		{"https://example.com/api/../admin", false},
+		{"https://example.com/api/%2e%2e/admin", false},
+		{"https://evil-example.com/api/v1", false},
+		{"https://example.com.evil.com/api/v1", false},
+		{"https://example.com:8443/api/v1", false},
+		{"http://example.com/api/v1", false},
+		{"https://user:pw@example.com/api/v1", false},
+		{"http://localhost:11434/api/tags", true},
+		{"http://localhost/api/tags", false},
+		{"http://localhost:11435/api/tags", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tt.url, err)
+		}
+		if got := urlAllowed(rules, u); got != tt.want {
+			t.Errorf("urlAllowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestParseURLAllowListErrors(t *testing.T) {
+	for _, entry := range []string{"example.com", "ftp://example.com", "https://user@example.com", "https://"} {
+		if _, err := parseURLAllowList([]string{entry}); err == nil {
+			t.Errorf("parseURLAllowList(%q) expected error", entry)
+		}
+	}
+}
+
+func TestToolReadFileSandbox(t *testing.T) {
+	dir := t.TempDir()
+	sandbox := filepath.Join(dir, "sandbox")
+	outside := filepath.Join(dir, "secret.txt")
+	mustWrite := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite(filepath.Join(sandbox, "notes", "a.txt"), "hello")
+	mustWrite(outside, "secret")
+	if err := os.Symlink(outside, filepath.Join(sandbox, "link.txt")); err != nil {
+		t.Skipf("symlink not supported: %v", err)
+	}
+	if err := os.Symlink(dir, filepath.Join(sandbox, "parent")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(sandbox, "notes", "a.txt"), filepath.Join(sandbox, "inside.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr string
+	}{
+		{path: "notes/a.txt", want: "hello"},
+		{path: "/notes/a.txt", want: "hello"},
+		{path: "notes/../notes/a.txt", want: "hello"},
+		{path: "inside.txt", want: "hello"},
+		{path: "notes", want: "a.txt"},
+		{path: "../secret.txt", wantErr: "文件不存在"},
+		{path: "notes/../../secret.txt", wantErr: "文件不存在"},
+		{path: outside, wantErr: "文件不存在"},
+		{path: "link.txt", wantErr: "沙箱以外"},
+		{path: "parent/secret.txt", wantErr: "沙箱以外"},
+		{path: "missing.txt", wantErr: "文件不存在"},
+	}
+	for _, tt := range tests {
+		got, err := toolReadFile(sandbox, map[string]interface{}{"path": tt.path})
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("toolReadFile(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("toolReadFile(%q): %v", tt.path, err)
+		} else if got != tt.want {
+			t.Errorf("toolReadFile(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -19,16 +19,283 @@ type Conversation struct {
 	Title        string    `json:"title"`
 	Messages     []Message `json:"messages"`
 	ModelName    string    `json:"modelName"`
-	SystemPrompt string    `json:"systemPrompt"` // JSON string of the active system prompt
-	ModelParams  string    `json:"modelParams"`  // JSON string of the model parameters
+	ServerID     string    `json:"serverId,omitempty"`    // 对话使用的 Ollama 服务器, 为空时使用活动服务器
+	SystemPrompt string    `json:"systemPrompt"`          // JSON string of the active system prompt
+	ModelParams  string    `json:"modelParams"`           // JSON string of the model parameters
+	Tools        []string  `json:"tools,omitempty"`       // 对话启用的工具名称
+	Collections  []string  `json:"collections,omitempty"` // 对话关联的知识库集合ID
 	Timestamp    int64     `json:"timestamp"`
 }
 
 // Message 聊天消息结构
 type Message struct {
-	Role      string `json:"role"`
-	Content   string `json:"content"`
-	Timestamp int64  `json:"timestamp"`
+	Role        string       `json:"role"`
+	Content     string       `json:"content"`
+	Thinking    string       `json:"thinking,omitempty"` // 推理模型的思考过程, 与正文分开保存
+	Timestamp   int64        `json:"timestamp"`
+	Attachments []Attachment `json:"attachments,omitempty"` // 图片等附件, 发送时作为 images 传给 /api/chat
+	ToolCalls   []ToolCall   `json:"toolCalls,omitempty"`   // 助手消息中模型发起的工具调用
+	ToolName    string       `json:"toolName,omitempty"`    // role 为 tool 时对应的工具名称
+}
+
+// Attachment 消息附件 (目前仅支持图片)
+type Attachment struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MimeType  string `json:"mimeType"`
+	Size      int64  `json:"size"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"` // 缩略图 data URL, 用于历史记录展示
+	Path      string `json:"path,omitempty"`      // 保存在本地附件目录中的文件路径
+	Data      string `json:"data,omitempty"`      // 未落盘时的 base64 内容
+}
+
+// ChatRequest 聊天请求
+type ChatRequest struct {
+	ConversationID string    `json:"conversationId,omitempty"`
+	ModelName      string    `json:"modelName"`
+	Messages       []Message `json:"messages"`
+	Stream         bool      `json:"stream"`
+	Tools          []string  `json:"tools,omitempty"`       // 本次请求启用的工具名称
+	Think          *bool     `json:"think,omitempty"`       // 是否请求推理模型单独返回思考过程, 为空时使用模型默认行为
+	Collections    []string  `json:"collections,omitempty"` // 本次请求检索的知识库集合ID
+}
+
+// ChatResponse 阻塞式聊天的响应
+type ChatResponse struct {
+	Content   string         `json:"content"`
+	Thinking  string         `json:"thinking,omitempty"`
+	Messages  []Message      `json:"messages,omitempty"`  // 最终回复之前产生的工具调用及工具结果消息
+	Citations []KnowledgeHit `json:"citations,omitempty"` // 注入上下文的知识库片段, 序号与回复中的 [n] 对应
+}
+
+// KnowledgeCollection 知识库集合, 同一集合内的文档使用同一个嵌入模型
+type KnowledgeCollection struct {
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	ServerID       string              `json:"serverId"`       // 用于生成向量的 Ollama 服务器, 为空时使用活动服务器
+	EmbeddingModel string              `json:"embeddingModel"` // 嵌入模型名称
+	ChunkSize      int                 `json:"chunkSize"`      // 每个片段的最大字符数
+	ChunkOverlap   int                 `json:"chunkOverlap"`   // 相邻片段的重叠字符数
+	Documents      []KnowledgeDocument `json:"documents"`
+	CreatedAt      int64               `json:"createdAt"`
+	UpdatedAt      int64               `json:"updatedAt"`
+}
+
+// KnowledgeDocument 已导入知识库的文档
+type KnowledgeDocument struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	ChunkCount int    `json:"chunkCount"`
+	IngestedAt int64  `json:"ingestedAt"`
+}
+
+// KnowledgeChunk 文档片段及其向量
+type KnowledgeChunk struct {
+	ID           string    `json:"id"`
+	DocumentID   string    `json:"documentId"`
+	DocumentName string    `json:"documentName"`
+	Index        int       `json:"index"`
+	Content      string    `json:"content"`
+	Embedding    []float64 `json:"embedding"`
+}
+
+// KnowledgeHit 检索命中的片段
+type KnowledgeHit struct {
+	CollectionID   string  `json:"collectionId"`
+	CollectionName string  `json:"collectionName"`
+	DocumentID     string  `json:"documentId"`
+	DocumentName   string  `json:"documentName"`
+	ChunkIndex     int     `json:"chunkIndex"`
+	Content        string  `json:"content"`
+	Score          float64 `json:"score"`
+}
+
+// IngestReport 文档导入结果
+type IngestReport struct {
+	CollectionID string              `json:"collectionId"`
+	Documents    []KnowledgeDocument `json:"documents"`        // 成功导入的文档
+	Errors       []string            `json:"errors,omitempty"` // 导入失败的文件及原因
+}
+
+// EmbeddingResult 一次向量化的结果
+type EmbeddingResult struct {
+	ServerID   string      `json:"serverId"`
+	Model      string      `json:"model"`
+	Inputs     []string    `json:"inputs"`
+	Embeddings [][]float64 `json:"embeddings"`
+	Dimensions int         `json:"dimensions"`
+	Similarity [][]float64 `json:"similarity"` // 输入两两之间的余弦相似度矩阵
+	DurationMs int64       `json:"durationMs"`
+}
+
+// EmbeddingSet 保存下来的一组输入及其向量, 用于对比不同嵌入模型
+type EmbeddingSet struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	ServerID   string      `json:"serverId"`
+	Model      string      `json:"model"`
+	Inputs     []string    `json:"inputs"`
+	Embeddings [][]float64 `json:"embeddings,omitempty"` // 列表接口中省略
+	Dimensions int         `json:"dimensions"`
+	CreatedAt  int64       `json:"createdAt"`
+}
+
+// EmbeddingComparison 多个向量集合在相同输入上的对比结果
+type EmbeddingComparison struct {
+	Inputs    []string      `json:"inputs"`
+	Sets      []string      `json:"sets"`      // 参与对比的集合名称, 与 Matrices 顺序一致
+	Models    []string      `json:"models"`    // 各集合使用的嵌入模型
+	Matrices  [][][]float64 `json:"matrices"`  // 各集合的相似度矩阵
+	Agreement []float64     `json:"agreement"` // 各集合相似度矩阵与第一个集合的皮尔逊相关系数
+}
+
+// PersonaTemplate 对话模板 (角色), 用于快速创建预设好的对话
+type PersonaTemplate struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	ServerID     string                  `json:"serverId"` // 为空时使用活动服务器
+	ModelName    string                  `json:"modelName"`
+	SystemPrompt string                  `json:"systemPrompt"`
+	Params       ConversationModelParams `json:"params"`       // 只保存显式设置的参数, 其余使用模型默认值
+	SeedMessages []Message               `json:"seedMessages"` // 新对话中预置的消息
+	Tools        []string                `json:"tools"`
+	CreatedAt    int64                   `json:"createdAt"`
+	UpdatedAt    int64                   `json:"updatedAt"`
+}
+
+// CompletionRequest 补全工作区请求, 对应 /api/generate
+type CompletionRequest struct {
+	RequestID string                  `json:"requestId"` // 由前端生成, 流式事件中原样返回
+	ServerID  string                  `json:"serverId"`  // 为空时使用活动服务器
+	Model     string                  `json:"model"`
+	Prompt    string                  `json:"prompt"`
+	Suffix    string                  `json:"suffix,omitempty"`
+	System    string                  `json:"system,omitempty"`
+	Template  string                  `json:"template,omitempty"`
+	Raw       bool                    `json:"raw"`
+	Context   []int                   `json:"context,omitempty"`
+	Params    ConversationModelParams `json:"params"`
+	Stream    bool                    `json:"stream"`
+}
+
+// CompletionResult 补全结果及统计信息
+type CompletionResult struct {
+	RequestID       string  `json:"requestId"`
+	Response        string  `json:"response"`
+	Context         []int   `json:"context,omitempty"` // 下一次请求可回传以延续上下文
+	DoneReason      string  `json:"doneReason,omitempty"`
+	PromptEvalCount int     `json:"promptEvalCount"`
+	EvalCount       int     `json:"evalCount"`
+	TotalDurationMs int64   `json:"totalDurationMs"`
+	TokensPerSecond float64 `json:"tokensPerSecond"`
+}
+
+// StructuredRequest 结构化输出请求, 要求模型按 JSON Schema 输出
+type StructuredRequest struct {
+	ModelName  string    `json:"modelName"`
+	Mode       string    `json:"mode"`               // "chat" 使用 /api/chat, "generate" 使用 /api/generate
+	Messages   []Message `json:"messages,omitempty"` // chat 模式的消息
+	Prompt     string    `json:"prompt,omitempty"`   // generate 模式的提示词
+	System     string    `json:"system,omitempty"`   // generate 模式的系统提示词
+	Schema     string    `json:"schema"`             // JSON Schema 文本
+	MaxRetries int       `json:"maxRetries"`         // 校验失败后的最大重试次数
+}
+
+// StructuredResult 结构化输出结果
+type StructuredResult struct {
+	Data     interface{} `json:"data"` // 解析后的 JSON 对象
+	Raw      string      `json:"raw"`  // 最后一次模型输出的原始文本
+	Valid    bool        `json:"valid"`
+	Attempts int         `json:"attempts"`
+	Errors   []string    `json:"errors,omitempty"` // 每次校验失败的原因
+}
+
+// --- Tool Calling Types ---
+
+// ToolCall 模型发起的一次工具调用, 与 Ollama 的 tool_calls 结构一致
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction 工具调用的函数名与参数
+type ToolCallFunction struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// ToolDefinition 工具定义, 内置工具和用户自定义工具共用
+type ToolDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON Schema
+	Builtin     bool                   `json:"builtin"`
+	SideEffect  bool                   `json:"sideEffect"`         // 有副作用的工具在执行前需要用户确认
+	Endpoint    string                 `json:"endpoint,omitempty"` // 自定义工具的 HTTP 地址, 调用时以 POST 发送参数
+	Enabled     bool                   `json:"enabled"`
+}
+
+// ToolSettings 工具执行的全局设置
+type ToolSettings struct {
+	SandboxDir    string   `json:"sandboxDir"`    // read_file 工具可访问的目录
+	HTTPAllowList []string `json:"httpAllowList"` // http_get 工具允许访问的 URL 前缀
+	MaxToolRounds int      `json:"maxToolRounds"` // 单次对话中工具调用的最大轮数
+}
+
+// ExportFormat 对话导出格式枚举
+type ExportFormat string
+
+const (
+	ExportFormatMarkdown ExportFormat = "markdown"
+	ExportFormatHtml     ExportFormat = "html"
+	ExportFormatJson     ExportFormat = "json"
+	ExportFormatJsonl    ExportFormat = "jsonl" // 用于微调数据集的 chat 格式
+)
+
+// ImportSource 对话导入来源枚举
+type ImportSource string
+
+const (
+	ImportSourceAuto      ImportSource = "auto" // 根据内容自动识别
+	ImportSourceChatGPT   ImportSource = "chatgpt"
+	ImportSourceOpenWebUI ImportSource = "openwebui"
+	ImportSourceJson      ImportSource = "json" // 本应用导出的 types.Conversation JSON
+	ImportSourceJsonl     ImportSource = "jsonl"
+)
+
+// ImportItemStatus 单个对话的导入状态
+type ImportItemStatus string
+
+const (
+	ImportItemStatusNew       ImportItemStatus = "new"
+	ImportItemStatusDuplicate ImportItemStatus = "duplicate"
+	ImportItemStatusInvalid   ImportItemStatus = "invalid"
+)
+
+// ImportItem 导入报告中的单个对话条目
+type ImportItem struct {
+	Title          string           `json:"title"`
+	MessageCount   int              `json:"messageCount"`
+	ContentHash    string           `json:"contentHash"`
+	Status         ImportItemStatus `json:"status"`
+	ConversationID string           `json:"conversationId,omitempty"` // 新建对话ID, 或重复时已存在的对话ID
+	Reason         string           `json:"reason,omitempty"`
+}
+
+// ImportReport 对话导入报告, DryRun 为 true 时仅预览不写入
+type ImportReport struct {
+	Source     ImportSource `json:"source"`
+	DryRun     bool         `json:"dryRun"`
+	Total      int          `json:"total"`
+	Imported   int          `json:"imported"`
+	Duplicates int          `json:"duplicates"`
+	Invalid    int          `json:"invalid"`
+	Items      []ImportItem `json:"items"`
 }
 
 // ListModelsResponse 模型列表响应
@@ -48,16 +315,309 @@ type OllamaServerConfig struct {
 
 // Prompt 代表一个已保存的提示词
 type Prompt struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Content     string   `json:"content"`
-	Description string   `json:"description"`
-	CreatedAt   int64    `json:"createdAt"`
-	UpdatedAt   int64    `json:"updatedAt"`
-	Models      []string `json:"models"`
-	Version     int      `json:"version"`
-	Tags        []string `json:"tags"`
-	CreatedBy   string   `json:"createdBy"`
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Content     string           `json:"content"`
+	Description string           `json:"description"`
+	CreatedAt   int64            `json:"createdAt"`
+	UpdatedAt   int64            `json:"updatedAt"`
+	Models      []string         `json:"models"`
+	Version     int              `json:"version"`
+	Tags        []string         `json:"tags"`
+	CreatedBy   string           `json:"createdBy"`
+	Variables   []PromptVariable `json:"variables,omitempty"` // 内容中 {{name}} 占位符的定义
+}
+
+// DiffLine 文本差异中的一行
+type DiffLine struct {
+	Type string `json:"type"` // "equal" / "insert" / "delete"
+	Text string `json:"text"`
+}
+
+// TextDiff 两段文本的逐行差异
+type TextDiff struct {
+	Lines   []DiffLine `json:"lines"`
+	Added   int        `json:"added"`
+	Removed int        `json:"removed"`
+}
+
+// PromptOptimizeResult 提示词优化结果
+type PromptOptimizeResult struct {
+	Original  string   `json:"original"`
+	Optimized string   `json:"optimized"`
+	Diff      TextDiff `json:"diff"`
+}
+
+// PromptRevision 提示词的一个不可变历史版本
+type PromptRevision struct {
+	PromptID  string `json:"promptId"`
+	Version   int    `json:"version"`
+	Prompt    Prompt `json:"prompt"` // 该版本保存时的完整快照
+	Author    string `json:"author"`
+	Note      string `json:"note,omitempty"` // 例如 "回滚自版本 3"
+	Timestamp int64  `json:"timestamp"`
+}
+
+// PromptRevisionDiff 两个版本之间的差异
+type PromptRevisionDiff struct {
+	FromVersion int      `json:"fromVersion"`
+	ToVersion   int      `json:"toVersion"`
+	Content     TextDiff `json:"content"`
+	Changes     []string `json:"changes"` // 内容以外发生变化的字段, 如 name / tags
+}
+
+// PromptQuery 提示词查询条件, 各条件之间为"与"关系
+type PromptQuery struct {
+	Text      string   `json:"text"`      // 在名称、描述和内容中全文搜索, 空格分隔的多个词都需要匹配
+	Tags      []string `json:"tags"`      // 需要包含全部标签
+	Models    []string `json:"models"`    // 适用模型包含其中任意一个即可
+	CreatedBy string   `json:"createdBy"` // 创建者, 不区分大小写
+	SortBy    string   `json:"sortBy"`    // updatedAt / createdAt / name / usage / relevance, 为空时有搜索词按相关度, 否则按更新时间
+	SortOrder string   `json:"sortOrder"` // asc / desc, 为空时名称升序, 其余降序
+	Offset    int      `json:"offset"`
+	Limit     int      `json:"limit"` // 0 表示不分页
+}
+
+// PromptFacet 分面统计中的一个取值
+type PromptFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PromptQueryResult 提示词查询结果, 分面统计基于分页前的全部匹配结果
+type PromptQueryResult struct {
+	Prompts  []Prompt      `json:"prompts"`
+	Total    int           `json:"total"`
+	Tags     []PromptFacet `json:"tags"`
+	Models   []PromptFacet `json:"models"`
+	Creators []PromptFacet `json:"creators"`
+}
+
+// 提示词使用来源
+const (
+	PromptUsageSourceConversation = "conversation"
+	PromptUsageSourceAdapter      = "adapter"
+)
+
+// PromptUsage 提示词的一条使用关联, 同一提示词版本在同一对话和模型上的多次使用合并为一条
+type PromptUsage struct {
+	PromptID          string `json:"promptId"`
+	Version           int    `json:"version"`
+	Source            string `json:"source"`                   // conversation / adapter
+	ConversationID    string `json:"conversationId,omitempty"` // 通过适配器调用时为空
+	ConversationTitle string `json:"conversationTitle,omitempty"`
+	ServerID          string `json:"serverId,omitempty"`
+	Model             string `json:"model"`
+	Count             int    `json:"count"`
+	FirstUsedAt       int64  `json:"firstUsedAt"`
+	LastUsedAt        int64  `json:"lastUsedAt"`
+}
+
+// PromptVersionUsage 提示词单个版本的使用次数
+type PromptVersionUsage struct {
+	Version int `json:"version"`
+	Count   int `json:"count"`
+}
+
+// PromptUsageStats 提示词的使用统计
+type PromptUsageStats struct {
+	PromptID          string               `json:"promptId"`
+	TotalCount        int                  `json:"totalCount"`        // 请求次数
+	ConversationCount int                  `json:"conversationCount"` // 使用过该提示词的对话数
+	LastUsedAt        int64                `json:"lastUsedAt"`
+	Versions          []PromptVersionUsage `json:"versions"` // 按版本号降序
+	Models            []PromptFacet        `json:"models"`
+	Recent            []PromptUsage        `json:"recent"` // 最近的使用关联, 最新的在前
+}
+
+// PromptBundleFormat 提示词库导入导出格式
+type PromptBundleFormat string
+
+const (
+	PromptBundleFormatJson     PromptBundleFormat = "json"
+	PromptBundleFormatYaml     PromptBundleFormat = "yaml"
+	PromptBundleFormatMarkdown PromptBundleFormat = "markdown" // 带 front matter 的 Markdown, 每个文件一个提示词
+)
+
+// PromptConflictStrategy 导入时与已有提示词 (ID 或名称相同) 冲突的处理方式
+type PromptConflictStrategy string
+
+const (
+	PromptConflictSkip      PromptConflictStrategy = "skip"
+	PromptConflictOverwrite PromptConflictStrategy = "overwrite" // 作为已有提示词的新版本保存
+	PromptConflictKeepBoth  PromptConflictStrategy = "keep_both" // 以新名称另存一份
+)
+
+// PromptBundle 提示词分享包
+type PromptBundle struct {
+	Format     string              `json:"format"` // 固定为 "tools-ollama.prompts"
+	Version    int                 `json:"version"`
+	ExportedAt int64               `json:"exportedAt"`
+	Prompts    []PromptBundleEntry `json:"prompts"`
+}
+
+// PromptBundleEntry 分享包中的一个提示词及其历史版本
+type PromptBundleEntry struct {
+	Prompt
+	Revisions []PromptRevision `json:"revisions,omitempty"`
+}
+
+// PromptImportItem 导入报告中的单个提示词条目
+type PromptImportItem struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`             // new / overwritten / renamed / skipped / invalid
+	PromptID string `json:"promptId,omitempty"` // 导入后或冲突的提示词ID
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PromptImportReport 提示词导入报告, DryRun 为 true 时仅预览不写入
+type PromptImportReport struct {
+	DryRun      bool               `json:"dryRun"`
+	Total       int                `json:"total"`
+	Imported    int                `json:"imported"`
+	Overwritten int                `json:"overwritten"`
+	Skipped     int                `json:"skipped"`
+	Invalid     int                `json:"invalid"`
+	Items       []PromptImportItem `json:"items"`
+}
+
+// ModelTarget 指定服务器上的一个模型
+type ModelTarget struct {
+	ServerID string `json:"serverId"`
+	Model    string `json:"model"`
+}
+
+// PromptExpectation 测试用例对输出的一项期望
+type PromptExpectation struct {
+	Type   string  `json:"type"`   // "contains" / "not_contains" / "regex" / "json_schema" / "judge"
+	Value  string  `json:"value"`  // 子串、正则、JSON Schema 文本或评分标准
+	Weight float64 `json:"weight"` // 计算得分时的权重, 为 0 时按 1 计算
+}
+
+// PromptTestCase 提示词测试用例
+type PromptTestCase struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	Variables    map[string]interface{} `json:"variables"`       // 渲染提示词使用的变量值
+	Input        string                 `json:"input,omitempty"` // 用户输入, 为空时直接以渲染后的提示词作为用户消息
+	Expectations []PromptExpectation    `json:"expectations"`
+}
+
+// PromptSuite 提示词的测试套件, 每个提示词一个
+type PromptSuite struct {
+	PromptID  string           `json:"promptId"`
+	Cases     []PromptTestCase `json:"cases"`
+	Judge     ModelTarget      `json:"judge"` // judge 类型期望使用的评审模型
+	UpdatedAt int64            `json:"updatedAt"`
+}
+
+// PromptCheckResult 单项期望的检查结果
+type PromptCheckResult struct {
+	Type   string  `json:"type"`
+	Passed bool    `json:"passed"`
+	Score  float64 `json:"score"` // 0 到 1
+	Detail string  `json:"detail,omitempty"`
+}
+
+// PromptCaseResult 单个用例在单个模型上的执行结果
+type PromptCaseResult struct {
+	CaseID     string              `json:"caseId"`
+	CaseName   string              `json:"caseName"`
+	Target     ModelTarget         `json:"target"`
+	Output     string              `json:"output"`
+	Checks     []PromptCheckResult `json:"checks"`
+	Score      float64             `json:"score"`
+	Passed     bool                `json:"passed"`
+	Error      string              `json:"error,omitempty"`
+	DurationMs int64               `json:"durationMs"`
+}
+
+// PromptTargetScore 某个模型在整个套件上的得分
+type PromptTargetScore struct {
+	Target ModelTarget `json:"target"`
+	Score  float64     `json:"score"`
+	Passed int         `json:"passed"`
+	Total  int         `json:"total"`
+	Delta  *float64    `json:"delta,omitempty"` // 与对比运行中同一模型的得分差
+}
+
+// PromptSuiteRun 一次套件运行的结果
+type PromptSuiteRun struct {
+	ID              string              `json:"id"`
+	PromptID        string              `json:"promptId"`
+	PromptVersion   int                 `json:"promptVersion"`
+	Results         []PromptCaseResult  `json:"results"`
+	Scores          []PromptTargetScore `json:"scores"`
+	Score           float64             `json:"score"`
+	BaselineRunID   string              `json:"baselineRunId,omitempty"` // 对比的历史运行 (优先取较早版本的最近一次运行)
+	BaselineVersion int                 `json:"baselineVersion,omitempty"`
+	ScoreDelta      *float64            `json:"scoreDelta,omitempty"`
+	StartedAt       int64               `json:"startedAt"`
+	DurationMs      int64               `json:"durationMs"`
+}
+
+// PromptGenerationRequest 多模型生成提示词请求
+type PromptGenerationRequest struct {
+	RequestID string        `json:"requestId"` // 为空时自动生成, 所有事件都会带上该ID
+	Idea      string        `json:"idea"`
+	Targets   []ModelTarget `json:"targets"`         // 并发生成候选提示词的模型
+	Judge     *ModelTarget  `json:"judge,omitempty"` // 为空时不排名
+	ProfileID string        `json:"profileId"`       // 元提示词配置, 为空时使用默认配置
+}
+
+// PromptCandidate 一个模型生成的候选提示词
+type PromptCandidate struct {
+	Index      int         `json:"index"`
+	Target     ModelTarget `json:"target"`
+	Content    string      `json:"content"`
+	Error      string      `json:"error,omitempty"`
+	DurationMs int64       `json:"durationMs"`
+	Rank       int         `json:"rank,omitempty"` // 评审排名, 从 1 开始
+	Score      float64     `json:"score,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+}
+
+// PromptGenerationResult 多模型生成的最终结果
+type PromptGenerationResult struct {
+	RequestID  string            `json:"requestId"`
+	Candidates []PromptCandidate `json:"candidates"`
+	Judged     bool              `json:"judged"`
+	JudgeError string            `json:"judgeError,omitempty"`
+}
+
+// MetaPromptProfile 生成提示词时使用的元提示词配置
+type MetaPromptProfile struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Language     string   `json:"language"`     // 生成的提示词使用的语言: zh / en
+	Sections     []string `json:"sections"`     // 要求提示词包含的段落, 如 role / task / constraints / format; 为空时不限制结构
+	ModelFamily  string   `json:"modelFamily"`  // 生成的提示词面向的模型系列, 如 llama / qwen; 为空表示通用
+	Instructions string   `json:"instructions"` // 附加的风格要求
+	BuiltIn      bool     `json:"builtIn"`      // 内置配置, 删除时恢复默认内容
+	CreatedAt    int64    `json:"createdAt"`
+	UpdatedAt    int64    `json:"updatedAt"`
+}
+
+// PromptVariableType 提示词变量类型
+type PromptVariableType string
+
+const (
+	PromptVariableString  PromptVariableType = "string"  // 单行文本
+	PromptVariableText    PromptVariableType = "text"    // 多行文本
+	PromptVariableNumber  PromptVariableType = "number"  // 数字
+	PromptVariableBoolean PromptVariableType = "boolean" // true / false
+	PromptVariableEnum    PromptVariableType = "enum"    // 只能取 Options 中的值
+)
+
+// PromptVariable 提示词变量定义
+type PromptVariable struct {
+	Name        string             `json:"name"`
+	Label       string             `json:"label,omitempty"` // 表单中显示的名称
+	Type        PromptVariableType `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Default     string             `json:"default,omitempty"`
+	Options     []string           `json:"options,omitempty"` // enum 类型的可选值
+	Required    bool               `json:"required"`
 }
 
 // ModelParams 模型参数
@@ -70,6 +630,20 @@ type ModelParams struct {
 	RepeatPenalty float64 `json:"repeatPenalty"`
 }
 
+// ConversationModelParams 对话级模型参数, 对应 Conversation.ModelParams 中保存的 JSON
+// 字段为空表示使用模型默认值
+type ConversationModelParams struct {
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+	TopK          *int     `json:"topK,omitempty"`
+	Context       *int     `json:"context,omitempty"`
+	NumPredict    *int     `json:"numPredict,omitempty"`
+	RepeatPenalty *float64 `json:"repeatPenalty,omitempty"`
+	Seed          *int     `json:"seed,omitempty"`
+	Stop          []string `json:"stop,omitempty"`
+	OutputMode    string   `json:"outputMode,omitempty"` // "stream" 或 "blocking", 仅前端使用
+}
+
 // RunningModel 运行中的模型
 type RunningModel struct {
 	Name      string      `json:"name"`
@@ -143,6 +717,7 @@ type ApiRequest struct {
 	QueryParams      []QueryParam    `json:"queryParams"`
 	Headers          []RequestHeader `json:"headers"`
 	Body             RequestBody     `json:"body"`
+	CollectionID     string          `json:"collectionId,omitempty"` // 所属请求集合, 发送时用集合变量替换 {{变量}}
 }
 
 // ApiResponse API响应结构
@@ -155,6 +730,103 @@ type ApiResponse struct {
 	Error             string          `json:"error,omitempty"`   // 错误信息
 }
 
+// ApiSavedRequest 保存在集合中的一个调试请求
+type ApiSavedRequest struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Folder      string     `json:"folder"` // 所在文件夹路径, 以 / 分隔, 为空表示集合根目录
+	Request     ApiRequest `json:"request"`
+	CreatedAt   int64      `json:"createdAt"`
+	UpdatedAt   int64      `json:"updatedAt"`
+}
+
+// ApiVariable 请求集合变量, 请求中的 {{Key}} 在发送时替换为 Value
+type ApiVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ApiCollection API 调试器的请求集合
+type ApiCollection struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Folders     []string          `json:"folders"` // 文件夹路径, 包括没有请求的空文件夹
+	Requests    []ApiSavedRequest `json:"requests"`
+	Variables   []ApiVariable     `json:"variables"`
+	CreatedAt   int64             `json:"createdAt"`
+	UpdatedAt   int64             `json:"updatedAt"`
+}
+
+// ApiHistoryEntry 已发送请求的历史记录
+type ApiHistoryEntry struct {
+	ID         string      `json:"id"`
+	Request    ApiRequest  `json:"request"`
+	Response   ApiResponse `json:"response"`
+	ServerName string      `json:"serverName"`
+	URL        string      `json:"url"`
+	Truncated  bool        `json:"truncated"` // 响应体过大时只保存了开头部分
+	ReplayOf   string      `json:"replayOf,omitempty"`
+	Timestamp  int64       `json:"timestamp"`
+}
+
+// ApiStreamStart 流式请求收到响应头时推送的信息
+type ApiStreamStart struct {
+	RequestID         string          `json:"requestId"`
+	StatusCode        int             `json:"statusCode"`
+	StatusText        string          `json:"statusText"`
+	Headers           []RequestHeader `json:"headers"`
+	Format            string          `json:"format"` // ndjson / sse
+	TimeToFirstByteMs int64           `json:"timeToFirstByteMs"`
+}
+
+// ApiStreamChunk 流式响应中的一个分块: NDJSON 的一行或 SSE 的一个事件
+type ApiStreamChunk struct {
+	RequestID string `json:"requestId"`
+	Index     int    `json:"index"`
+	Event     string `json:"event,omitempty"` // SSE 的事件名
+	Data      string `json:"data"`
+	Timestamp int64  `json:"timestamp"` // 收到分块的时间 (毫秒时间戳)
+	ElapsedMs int64  `json:"elapsedMs"` // 距离请求开始的毫秒数
+}
+
+// ApiStreamSummary 流式请求结束时的汇总, 被取消或出错时同样推送
+type ApiStreamSummary struct {
+	RequestID          string `json:"requestId"`
+	StatusCode         int    `json:"statusCode"`
+	ChunkCount         int    `json:"chunkCount"`
+	Bytes              int64  `json:"bytes"`
+	TimeToFirstByteMs  int64  `json:"timeToFirstByteMs"`
+	TimeToFirstChunkMs int64  `json:"timeToFirstChunkMs"`
+	TotalDurationMs    int64  `json:"totalDurationMs"`
+	Cancelled          bool   `json:"cancelled"`
+	Error              string `json:"error,omitempty"`
+}
+
+// ApiCodeSnippets 调试请求对应的命令行和代码片段
+type ApiCodeSnippets struct {
+	URL    string `json:"url"` // 最终请求的 URL
+	Curl   string `json:"curl"`
+	HTTPie string `json:"httpie"`
+	Go     string `json:"go"` // 使用 net/http 的完整 Go 程序
+}
+
+// CurlImportResult curl 命令解析结果
+type CurlImportResult struct {
+	Request  ApiRequest `json:"request"`
+	Warnings []string   `json:"warnings"` // 无法原样转换的内容, 如 multipart 表单
+}
+
+// PostmanImportReport Postman 集合导入结果
+type PostmanImportReport struct {
+	Collection         ApiCollection `json:"collection"`
+	Imported           int           `json:"imported"`           // 导入的请求数量
+	UnmatchedBaseURLs  []string      `json:"unmatchedBaseUrls"`  // 没有对应服务器配置的基础地址, 需要用户自行添加服务器
+	UnassignedRequests []string      `json:"unassignedRequests"` // 未关联服务器的请求名称
+	Warnings           []string      `json:"warnings"`           // 无法完整转换的内容, 如文件字段、脚本
+}
+
 // --- OpenAI Adapter Types ---
 
 // OpenAIAdapterConfig holds the user-configurable settings for the adapter.
@@ -27,6 +27,27 @@ func ToCoreMessages(messages []types.Message) []core.Message {
 	return coreMessages
 }
 
+// ToOllamaChatMessages 将types.Message转换为/api/chat请求消息, 保留工具调用和工具结果的关联并加载图片附件
+func ToOllamaChatMessages(messages []types.Message) ([]OllamaChatMessage, error) {
+	chatMessages := make([]OllamaChatMessage, len(messages))
+	for i, msg := range messages {
+		chatMessages[i] = OllamaChatMessage{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			ToolCalls: msg.ToolCalls,
+			ToolName:  msg.ToolName,
+		}
+		if len(msg.Attachments) > 0 {
+			images, err := attachmentImages(msg.Attachments)
+			if err != nil {
+				return nil, err
+			}
+			chatMessages[i].Images = images
+		}
+	}
+	return chatMessages, nil
+}
+
 // EnsureHTTPPrefix 确保URL包含协议前缀
 func EnsureHTTPPrefix(url string) string {
 	if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {